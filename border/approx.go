@@ -0,0 +1,200 @@
+package border
+
+import (
+	"container/heap"
+	"image"
+	"maps"
+	"math"
+)
+
+// ApproxPolyDP returns a copy of the contour tree with the points of every contour simplified using the
+// Ramer-Douglas-Peucker algorithm. Points closer than epsilon (in pixels) to the simplified contour are removed.
+// As per OpenCV's approxPolyDP for closed contours, the contour is first split at the point farthest from
+// the starting point. The original contours are not modified.
+func (c *Contour) ApproxPolyDP(epsilon float64) *Contour {
+	return c.mapPoints(func(points []image.Point) []image.Point {
+		return approxPolyDP(points, epsilon)
+	})
+}
+
+// Visvalingam returns a copy of the contour tree with the points of every contour simplified using the
+// Visvalingam-Whyatt algorithm. Points are removed (smallest first) while the area of the triangle they form
+// with their neighbours is less than minArea (in pixels). The original contours are not modified.
+func (c *Contour) Visvalingam(minArea float64) *Contour {
+	return c.mapPoints(func(points []image.Point) []image.Point {
+		return visvalingam(points, minArea)
+	})
+}
+
+// mapPoints deep copies the contour tree, replacing the points of each contour with the result of f.
+func (c *Contour) mapPoints(f func(points []image.Point) []image.Point) *Contour {
+	newC := *c
+	newC.Points = f(c.Points)
+	newC.ConflictingContours = maps.Clone(c.ConflictingContours)
+	newC.Parent = nil
+	newC.Children = make([]*Contour, len(c.Children))
+	for i, child := range c.Children {
+		newChild := child.mapPoints(f)
+		newChild.Parent = &newC
+		newC.Children[i] = newChild
+	}
+	return &newC
+}
+
+// approxPolyDP simplifies a closed ring of points.
+func approxPolyDP(points []image.Point, epsilon float64) []image.Point {
+	n := len(points)
+	if n <= 3 {
+		return append([]image.Point{}, points...)
+	}
+
+	// split at the point farthest from the start, then simplify each half.
+	farthest := 0
+	maxDist := -1
+	for i, p := range points {
+		d := p.Sub(points[0])
+		dist := d.X*d.X + d.Y*d.Y
+		if dist > maxDist {
+			maxDist = dist
+			farthest = i
+		}
+	}
+
+	keep := make([]bool, n)
+	keep[0] = true
+	keep[farthest] = true
+	douglasPeucker(points, 0, farthest, epsilon, keep)
+	douglasPeucker(points, farthest, n, epsilon, keep)
+
+	simplified := []image.Point{}
+	for i, p := range points {
+		if keep[i] {
+			simplified = append(simplified, p)
+		}
+	}
+	return simplified
+}
+
+// douglasPeucker marks the points between start and end (exclusive) to keep. end may be len(points)
+// to refer to the first point, closing the ring.
+func douglasPeucker(points []image.Point, start int, end int, epsilon float64, keep []bool) {
+	type span struct{ start, end int }
+	stack := []span{{start, end}}
+	for len(stack) > 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		a := points[s.start]
+		b := points[s.end%len(points)]
+		maxDist := -1.0
+		idx := -1
+		for i := s.start + 1; i < s.end; i++ {
+			dist := distanceToLine(points[i], a, b)
+			if dist > maxDist {
+				maxDist = dist
+				idx = i
+			}
+		}
+
+		if idx != -1 && maxDist > epsilon {
+			keep[idx] = true
+			stack = append(stack, span{s.start, idx}, span{idx, s.end})
+		}
+	}
+}
+
+// visvalingam simplifies a closed ring of points. At least 3 points are kept.
+func visvalingam(points []image.Point, minArea float64) []image.Point {
+	n := len(points)
+	if n <= 3 {
+		return append([]image.Point{}, points...)
+	}
+
+	prev := make([]int, n)
+	next := make([]int, n)
+	for i := range points {
+		prev[i] = (i + n - 1) % n
+		next[i] = (i + 1) % n
+	}
+
+	triangleArea := func(i int) float64 {
+		return math.Abs(float64(cross(points[prev[i]], points[i], points[next[i]]))) / 2
+	}
+
+	h := make(areaHeap, n)
+	entries := make([]*areaEntry, n)
+	for i := range points {
+		entries[i] = &areaEntry{idx: i, area: triangleArea(i), heapIdx: i}
+		h[i] = entries[i]
+	}
+	heap.Init(&h)
+
+	removed := make([]bool, n)
+	remaining := n
+	lastArea := 0.0
+	for remaining > 3 {
+		e := h[0]
+		if e.area >= minArea {
+			break
+		}
+		heap.Pop(&h)
+		removed[e.idx] = true
+		remaining--
+
+		// a point can't be more significant than one removed before it, so neighbours use at least this area.
+		lastArea = max(lastArea, e.area)
+		p, nx := prev[e.idx], next[e.idx]
+		next[p] = nx
+		prev[nx] = p
+		for _, i := range []int{p, nx} {
+			entries[i].area = max(triangleArea(i), lastArea)
+			heap.Fix(&h, entries[i].heapIdx)
+		}
+	}
+
+	simplified := []image.Point{}
+	for i, p := range points {
+		if !removed[i] {
+			simplified = append(simplified, p)
+		}
+	}
+	return simplified
+}
+
+// areaEntry is the effective area of a point in the Visvalingam-Whyatt heap.
+type areaEntry struct {
+	idx     int
+	area    float64
+	heapIdx int
+}
+
+// areaHeap is a min heap of areaEntry, implementing heap.Interface.
+type areaHeap []*areaEntry
+
+func (h areaHeap) Len() int { return len(h) }
+
+func (h areaHeap) Less(i int, j int) bool {
+	if h[i].area != h[j].area {
+		return h[i].area < h[j].area
+	}
+	return h[i].idx < h[j].idx
+}
+
+func (h areaHeap) Swap(i int, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].heapIdx = i
+	h[j].heapIdx = j
+}
+
+func (h *areaHeap) Push(x any) {
+	e := x.(*areaEntry)
+	e.heapIdx = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *areaHeap) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return e
+}
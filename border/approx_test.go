@@ -0,0 +1,148 @@
+package border
+
+import (
+	"image"
+	"slices"
+	"testing"
+)
+
+// squarePoints returns the points around the edge of a square, anti-clockwise from the top left, with a
+// small bump of height bump in the middle of the bottom edge.
+func squarePoints(size int, bump int) []image.Point {
+	points := []image.Point{}
+	for y := 0; y < size; y++ {
+		points = append(points, image.Point{0, y})
+	}
+	for x := 0; x < size; x++ {
+		y := size
+		if x == size/2 {
+			y += bump
+		}
+		points = append(points, image.Point{x, y})
+	}
+	for y := size; y > 0; y-- {
+		points = append(points, image.Point{size, y})
+	}
+	for x := size; x > 0; x-- {
+		points = append(points, image.Point{x, 0})
+	}
+	return points
+}
+
+// TestApproximation tests both simplification algorithms on the same shapes.
+func TestApproximation(t *testing.T) {
+	testCases := []struct {
+		name     string
+		points   []image.Point
+		approx   func(c *Contour) *Contour
+		expected []image.Point
+	}{
+		{
+			name:     "success douglas peucker square",
+			points:   squarePoints(10, 0),
+			approx:   func(c *Contour) *Contour { return c.ApproxPolyDP(0.5) },
+			expected: []image.Point{{0, 0}, {0, 10}, {10, 10}, {10, 0}},
+		},
+		{
+			name:     "success douglas peucker removes small bump",
+			points:   squarePoints(10, 1),
+			approx:   func(c *Contour) *Contour { return c.ApproxPolyDP(1.5) },
+			expected: []image.Point{{0, 0}, {0, 10}, {10, 10}, {10, 0}},
+		},
+		{
+			name:     "success douglas peucker keeps large bump",
+			points:   squarePoints(10, 3),
+			approx:   func(c *Contour) *Contour { return c.ApproxPolyDP(1.5) },
+			expected: []image.Point{{0, 0}, {0, 10}, {4, 10}, {5, 13}, {6, 10}, {10, 10}, {10, 0}},
+		},
+		{
+			name:     "success douglas peucker triangle unchanged",
+			points:   []image.Point{{0, 0}, {0, 5}, {5, 0}},
+			approx:   func(c *Contour) *Contour { return c.ApproxPolyDP(10) },
+			expected: []image.Point{{0, 0}, {0, 5}, {5, 0}},
+		},
+		{
+			name:     "success visvalingam square",
+			points:   squarePoints(10, 0),
+			approx:   func(c *Contour) *Contour { return c.Visvalingam(0.5) },
+			expected: []image.Point{{0, 0}, {0, 10}, {10, 10}, {10, 0}},
+		},
+		{
+			name:     "success visvalingam removes small bump",
+			points:   squarePoints(10, 1),
+			approx:   func(c *Contour) *Contour { return c.Visvalingam(1.5) },
+			expected: []image.Point{{0, 0}, {0, 10}, {10, 10}, {10, 0}},
+		},
+		{
+			name:     "success visvalingam keeps large bump",
+			points:   squarePoints(10, 3),
+			approx:   func(c *Contour) *Contour { return c.Visvalingam(2) },
+			expected: []image.Point{{0, 0}, {0, 10}, {4, 10}, {5, 13}, {6, 10}, {10, 10}, {10, 0}},
+		},
+		{
+			name:     "success visvalingam keeps 3 points",
+			points:   squarePoints(10, 0),
+			approx:   func(c *Contour) *Contour { return c.Visvalingam(1000) },
+			expected: []image.Point{{0, 10}, {10, 10}, {10, 0}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := NewContour(2)
+			c.Points = tc.points
+
+			simplified := tc.approx(c)
+			if !slices.Equal(simplified.Points, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, simplified.Points)
+			}
+
+			if len(c.Points) != len(tc.points) {
+				t.Errorf("expected original contour to be unmodified")
+			}
+		})
+	}
+}
+
+// TestApproximationTree tests the tree structure is copied.
+func TestApproximationTree(t *testing.T) {
+	img, err := LoadImage(`../testimages/unittest1.png`, 0, 0)
+	if err != nil {
+		t.Fatalf("Unable to load test image: %s", err.Error())
+	}
+
+	root, err := FindContours(img)
+	if err != nil {
+		t.Fatalf("Unable to find contours: %s", err.Error())
+	}
+
+	for _, simplified := range []*Contour{root.ApproxPolyDP(1), root.Visvalingam(1)} {
+		compareTrees(t, simplified, root)
+	}
+}
+
+// compareTrees checks the structure of a simplified tree matches the original, and it shares no contours.
+func compareTrees(t *testing.T, simplified *Contour, original *Contour) {
+	if simplified == original {
+		t.Fatalf("expected contour %d to be copied", original.Id)
+	}
+
+	if simplified.Id != original.Id || simplified.ParentId != original.ParentId || simplified.BorderType != original.BorderType {
+		t.Errorf("expected contour %d to match original", original.Id)
+	}
+
+	if len(simplified.Points) > len(original.Points) {
+		t.Errorf("expected contour %d to have at most %d points, got %d", original.Id, len(original.Points), len(simplified.Points))
+	}
+
+	if len(simplified.Children) != len(original.Children) {
+		t.Fatalf("expected contour %d to have %d children, got %d", original.Id, len(original.Children), len(simplified.Children))
+	}
+
+	for i, child := range simplified.Children {
+		if child.Parent != simplified {
+			t.Errorf("expected contour %d to have simplified parent", child.Id)
+		}
+		compareTrees(t, child, original.Children[i])
+	}
+}
@@ -0,0 +1,310 @@
+package border
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"maps"
+	"slices"
+)
+
+var (
+	ErrNotAdjacent      = errors.New("points are not adjacent")
+	ErrInvalidChainCode = errors.New("invalid chain code encoding")
+)
+
+// ChainCode is a Freeman chain code representation of a contour: the first point followed by the direction
+// (0-7, clockwise from north as per dirDelta) to each subsequent point. The final move back to the start
+// is not stored.
+type ChainCode struct {
+	Start image.Point
+	Codes []uint8
+}
+
+// NewChainCode generates the chain code for points, which must each be 8-connected to the previous point.
+func NewChainCode(points []image.Point) (ChainCode, error) {
+	if len(points) == 0 {
+		return ChainCode{}, nil
+	}
+
+	cc := ChainCode{Start: points[0], Codes: make([]uint8, 0, len(points)-1)}
+	for i := 1; i < len(points); i++ {
+		dir, err := calcDir(points[i-1], points[i])
+		if err != nil {
+			return ChainCode{}, fmt.Errorf("%w: %v and %v", ErrNotAdjacent, points[i-1], points[i])
+		}
+		cc.Codes = append(cc.Codes, uint8(dir))
+	}
+	return cc, nil
+}
+
+// Points expands the chain code back to the points of the contour.
+func (cc ChainCode) Points() []image.Point {
+	points := make([]image.Point, 0, len(cc.Codes)+1)
+	p := cc.Start
+	points = append(points, p)
+	for _, code := range cc.Codes {
+		p = p.Add(dirDelta[code])
+		points = append(points, p)
+	}
+	return points
+}
+
+// MarshalBinary encodes the chain code as the start point (varints) and number of codes (uvarint) followed
+// by the codes packed 3 bits each.
+func (cc ChainCode) MarshalBinary() ([]byte, error) {
+	return cc.appendBinary(nil), nil
+}
+
+// UnmarshalBinary decodes a chain code generated by MarshalBinary.
+func (cc *ChainCode) UnmarshalBinary(data []byte) error {
+	n, err := cc.readBinary(data)
+	if err != nil {
+		return err
+	}
+	if n != len(data) {
+		return fmt.Errorf("%w: %d unexpected trailing bytes", ErrInvalidChainCode, len(data)-n)
+	}
+	return nil
+}
+
+// appendBinary appends the encoded chain code to buf.
+func (cc ChainCode) appendBinary(buf []byte) []byte {
+	buf = binary.AppendVarint(buf, int64(cc.Start.X))
+	buf = binary.AppendVarint(buf, int64(cc.Start.Y))
+	buf = binary.AppendUvarint(buf, uint64(len(cc.Codes)))
+
+	packed := make([]byte, (len(cc.Codes)*3+7)/8)
+	for i, code := range cc.Codes {
+		bit := i * 3
+		v := uint16(code&7) << (bit % 8)
+		packed[bit/8] |= byte(v)
+		if v > 0xff {
+			packed[bit/8+1] |= byte(v >> 8)
+		}
+	}
+	return append(buf, packed...)
+}
+
+// readBinary decodes a chain code from the start of data, returning the number of bytes read.
+func (cc *ChainCode) readBinary(data []byte) (int, error) {
+	offset := 0
+	readVarint := func() (int64, error) {
+		v, n := binary.Varint(data[offset:])
+		if n <= 0 {
+			return 0, ErrInvalidChainCode
+		}
+		offset += n
+		return v, nil
+	}
+
+	x, err := readVarint()
+	if err != nil {
+		return 0, err
+	}
+	y, err := readVarint()
+	if err != nil {
+		return 0, err
+	}
+	count, n := binary.Uvarint(data[offset:])
+	if n <= 0 {
+		return 0, ErrInvalidChainCode
+	}
+	offset += n
+
+	packedLen := (int(count)*3 + 7) / 8
+	if count > uint64(len(data))*8/3 || offset+packedLen > len(data) {
+		return 0, fmt.Errorf("%w: %d codes but only %d bytes", ErrInvalidChainCode, count, len(data)-offset)
+	}
+
+	packed := data[offset : offset+packedLen]
+	codes := make([]uint8, count)
+	for i := range codes {
+		bit := i * 3
+		v := uint16(packed[bit/8])
+		if bit/8+1 < len(packed) {
+			v |= uint16(packed[bit/8+1]) << 8
+		}
+		codes[i] = uint8(v>>(bit%8)) & 7
+	}
+
+	cc.Start = image.Point{int(x), int(y)}
+	cc.Codes = codes
+	return offset + packedLen, nil
+}
+
+// EncodeChainCodes replaces the Points of every contour in the tree with the equivalent ChainCode.
+func EncodeChainCodes(c *Contour) error {
+	if c.ChainCode == nil && len(c.Points) > 0 {
+		cc, err := NewChainCode(c.Points)
+		if err != nil {
+			return err
+		}
+		c.ChainCode = &cc
+		c.Points = nil
+	}
+
+	for _, child := range c.Children {
+		if err := EncodeChainCodes(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeChainCodes replaces the ChainCode of every contour in the tree with the equivalent Points.
+func DecodeChainCodes(c *Contour) {
+	if c.ChainCode != nil {
+		c.Points = c.ChainCode.Points()
+		c.ChainCode = nil
+	}
+
+	for _, child := range c.Children {
+		DecodeChainCodes(child)
+	}
+}
+
+const (
+	flagParentCollision = 1 << iota
+	flagUsable
+	flagHasPoints
+)
+
+// MarshalBinary encodes the contour and all children, storing the points as chain codes. Contours can be
+// stored with either Points or a ChainCode.
+func (c *Contour) MarshalBinary() ([]byte, error) {
+	return c.appendBinary(nil)
+}
+
+// UnmarshalBinary decodes a contour tree generated by MarshalBinary. The decoded contours have their
+// Points set (use EncodeChainCodes to convert them back to chain codes).
+func (c *Contour) UnmarshalBinary(data []byte) error {
+	n, err := c.readBinary(data, nil)
+	if err != nil {
+		return err
+	}
+	if n != len(data) {
+		return fmt.Errorf("%w: %d unexpected trailing bytes", ErrInvalidChainCode, len(data)-n)
+	}
+	return nil
+}
+
+// appendBinary appends the encoded contour and its children to buf.
+func (c *Contour) appendBinary(buf []byte) ([]byte, error) {
+	buf = binary.AppendUvarint(buf, uint64(c.Id))
+	buf = binary.AppendUvarint(buf, uint64(c.ParentId))
+	buf = append(buf, byte(c.BorderType))
+
+	flags := byte(0)
+	if c.ParentCollision {
+		flags |= flagParentCollision
+	}
+	if c.Usable {
+		flags |= flagUsable
+	}
+
+	cc := c.ChainCode
+	if cc == nil && len(c.Points) > 0 {
+		generated, err := NewChainCode(c.Points)
+		if err != nil {
+			return nil, err
+		}
+		cc = &generated
+	}
+	if cc != nil {
+		flags |= flagHasPoints
+	}
+	buf = append(buf, flags)
+	if cc != nil {
+		buf = cc.appendBinary(buf)
+	}
+
+	conflicting := slices.Sorted(maps.Keys(c.ConflictingContours))
+	buf = binary.AppendUvarint(buf, uint64(len(conflicting)))
+	for _, id := range conflicting {
+		buf = binary.AppendUvarint(buf, uint64(id))
+	}
+
+	buf = binary.AppendUvarint(buf, uint64(len(c.Children)))
+	for _, child := range c.Children {
+		var err error
+		buf, err = child.appendBinary(buf)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return buf, nil
+}
+
+// readBinary decodes a contour and its children from the start of data, returning the number of bytes read.
+func (c *Contour) readBinary(data []byte, parent *Contour) (int, error) {
+	offset := 0
+	readUvarint := func() (int, error) {
+		v, n := binary.Uvarint(data[offset:])
+		if n <= 0 {
+			return 0, ErrInvalidChainCode
+		}
+		offset += n
+		return int(v), nil
+	}
+
+	*c = *NewContour(0)
+	c.Parent = parent
+
+	var err error
+	if c.Id, err = readUvarint(); err != nil {
+		return 0, err
+	}
+	if c.ParentId, err = readUvarint(); err != nil {
+		return 0, err
+	}
+	if offset+2 > len(data) {
+		return 0, ErrInvalidChainCode
+	}
+	c.BorderType = int(data[offset])
+	flags := data[offset+1]
+	offset += 2
+	c.ParentCollision = flags&flagParentCollision != 0
+	c.Usable = flags&flagUsable != 0
+
+	if flags&flagHasPoints != 0 {
+		cc := ChainCode{}
+		n, err := cc.readBinary(data[offset:])
+		if err != nil {
+			return 0, err
+		}
+		offset += n
+		c.Points = cc.Points()
+	}
+
+	numConflicting, err := readUvarint()
+	if err != nil {
+		return 0, err
+	}
+	for i := 0; i < numConflicting; i++ {
+		id, err := readUvarint()
+		if err != nil {
+			return 0, err
+		}
+		c.ConflictingContours[id] = true
+	}
+
+	numChildren, err := readUvarint()
+	if err != nil {
+		return 0, err
+	}
+	if numChildren > len(data)-offset {
+		return 0, ErrInvalidChainCode
+	}
+	for i := 0; i < numChildren; i++ {
+		child := &Contour{}
+		n, err := child.readBinary(data[offset:], c)
+		if err != nil {
+			return 0, err
+		}
+		offset += n
+		c.Children = append(c.Children, child)
+	}
+	return offset, nil
+}
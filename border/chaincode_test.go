@@ -0,0 +1,122 @@
+package border
+
+import (
+	"context"
+	"errors"
+	"image"
+	"slices"
+	"testing"
+)
+
+// TestChainCode tests converting points to and from chain codes, including binary encoding.
+func TestChainCode(t *testing.T) {
+	testCases := []struct {
+		name          string
+		points        []image.Point
+		expectedCodes []uint8
+		expectErr     error
+	}{
+		{
+			name:          "success all directions",
+			points:        []image.Point{{5, 5}, {5, 4}, {6, 3}, {7, 3}, {8, 4}, {8, 5}, {7, 6}, {6, 6}, {5, 5}},
+			expectedCodes: []uint8{0, 1, 2, 3, 4, 5, 6, 7},
+		},
+		{
+			name:          "success negative start",
+			points:        []image.Point{{-1, -300}, {-1, -299}},
+			expectedCodes: []uint8{4},
+		},
+		{
+			name:          "success single point",
+			points:        []image.Point{{1000000, 7}},
+			expectedCodes: []uint8{},
+		},
+		{
+			name:      "error with points not adjacent",
+			points:    []image.Point{{0, 0}, {0, 1}, {0, 3}},
+			expectErr: ErrNotAdjacent,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cc, err := NewChainCode(tc.points)
+			if tc.expectErr != nil {
+				if !errors.Is(err, tc.expectErr) {
+					t.Errorf("expected error %v, got %v", tc.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if !slices.Equal(cc.Codes, tc.expectedCodes) {
+				t.Errorf("expected codes %v, got %v", tc.expectedCodes, cc.Codes)
+			}
+
+			if !slices.Equal(cc.Points(), tc.points) {
+				t.Errorf("expected points %v, got %v", tc.points, cc.Points())
+			}
+
+			data, err := cc.MarshalBinary()
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			decoded := ChainCode{}
+			if err := decoded.UnmarshalBinary(data); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if decoded.Start != cc.Start || !slices.Equal(decoded.Codes, cc.Codes) {
+				t.Errorf("expected decoded %+v, got %+v", cc, decoded)
+			}
+
+			if err := decoded.UnmarshalBinary(data[:len(data)-1]); len(cc.Codes) > 0 && !errors.Is(err, ErrInvalidChainCode) {
+				t.Errorf("expected truncated data to return ErrInvalidChainCode, got %v", err)
+			}
+		})
+	}
+}
+
+// TestFindContoursChainCode tests contours found as chain codes match those found as points, and that
+// the tree can be encoded and decoded.
+func TestFindContoursChainCode(t *testing.T) {
+	for _, filename := range []string{`../testimages/unittest1.png`, `../testimages/image1.png`} {
+		t.Run(filename, func(t *testing.T) {
+			img, err := LoadImage(filename, 0, 0)
+			if err != nil {
+				t.Fatalf("Unable to load test image: %s", err.Error())
+			}
+
+			expected, err := FindContours(img.Clone())
+			if err != nil {
+				t.Fatalf("Unable to find contours: %s", err.Error())
+			}
+
+			encoded, err := FindContoursCtx(context.Background(), img, FindOptions{ChainCode: true})
+			if err != nil {
+				t.Fatalf("Unable to find contours: %s", err.Error())
+			}
+
+			data, err := encoded.MarshalBinary()
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			pointsSize := len(expected.GetAllPoints()) * 16
+			if len(data)*10 > pointsSize {
+				t.Errorf("expected encoding (%d bytes) to be a tenth the size of the points (%d bytes)", len(data), pointsSize)
+			}
+
+			decoded := &Contour{}
+			if err := decoded.UnmarshalBinary(data); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			DecodeChainCodes(encoded)
+			compareContourTrees(t, encoded, expected)
+			compareContourTrees(t, decoded, expected)
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package border
+
+import (
+	"image"
+	"image/color"
+)
+
+// ForegroundFunc reports if a colour is treated as foreground (content to find borders for) when converting
+// an image to a SuzukiImage. The colours of paletted images are supplied as PaletteColours.
+type ForegroundFunc func(c color.Color) bool
+
+// PaletteColour is the colour of a pixel of a paletted image along with the palette index of the pixel, so
+// palette entries that share a colour can be told apart.
+type PaletteColour struct {
+	color.Color
+	Index uint8
+}
+
+// Classifier creates the ForegroundFunc used for a specific image.
+// Most classifiers ignore the image, but some (eg. NewOtsuClassifier) need to inspect every pixel
+// before they can decide what is foreground.
+type Classifier func(img image.Image) ForegroundFunc
+
+// NewNonBlackClassifier treats any pixel that is not exactly RGB(0,0,0) as foreground. This is the default
+// used when no classifier is supplied.
+func NewNonBlackClassifier() Classifier {
+	return func(img image.Image) ForegroundFunc {
+		return isNonBlack
+	}
+}
+
+// NewLuminanceClassifier treats any pixel with a luminance (0-255) greater than threshold as foreground.
+// Useful for anti-aliased masks or JPEG noise where background is "nearly" black.
+func NewLuminanceClassifier(threshold uint8) Classifier {
+	return func(img image.Image) ForegroundFunc {
+		return func(c color.Color) bool {
+			return luminance(c) > threshold
+		}
+	}
+}
+
+// NewOtsuClassifier determines a luminance threshold for each image using Otsu's method
+// ( https://en.wikipedia.org/wiki/Otsu%27s_method ) and treats pixels above that threshold as foreground.
+func NewOtsuClassifier() Classifier {
+	return func(img image.Image) ForegroundFunc {
+		threshold := otsuThreshold(img)
+		return func(c color.Color) bool {
+			return luminance(c) > threshold
+		}
+	}
+}
+
+// NewAlphaClassifier treats any pixel with an alpha (0-255) greater than threshold as foreground, regardless
+// of colour. Used for masks that mark content with transparency rather than colour.
+func NewAlphaClassifier(threshold uint8) Classifier {
+	return func(img image.Image) ForegroundFunc {
+		return func(c color.Color) bool {
+			_, _, _, a := c.RGBA()
+			return uint8(a>>8) > threshold
+		}
+	}
+}
+
+// NewColourClassifier treats pixels exactly matching any of the supplied colours as foreground.
+func NewColourClassifier(colours ...color.Color) Classifier {
+	return func(img image.Image) ForegroundFunc {
+		return func(c color.Color) bool {
+			r, g, b, a := c.RGBA()
+			for _, colour := range colours {
+				r2, g2, b2, a2 := colour.RGBA()
+				if r == r2 && g == g2 && b == b2 && a == a2 {
+					return true
+				}
+			}
+			return false
+		}
+	}
+}
+
+// NewPaletteIndexClassifier treats pixels of a paletted image whose palette index is one of indices as
+// foreground. If the image is not paletted then no pixels are foreground.
+// The index of each pixel is taken from its PaletteColour, so indices sharing a colour with other palette
+// entries still match. Any other colour is matched to the nearest palette entry.
+func NewPaletteIndexClassifier(indices ...uint8) Classifier {
+	return func(img image.Image) ForegroundFunc {
+		paletted, ok := img.(*image.Paletted)
+		if !ok {
+			return func(c color.Color) bool {
+				return false
+			}
+		}
+
+		var wanted [256]bool
+		for _, i := range indices {
+			wanted[i] = true
+		}
+
+		return func(c color.Color) bool {
+			if pc, ok := c.(PaletteColour); ok {
+				return wanted[pc.Index]
+			}
+			return wanted[paletted.Palette.Index(c)]
+		}
+	}
+}
+
+// NewCustomClassifier wraps an arbitrary function as a Classifier.
+func NewCustomClassifier(f func(c color.Color) bool) Classifier {
+	return func(img image.Image) ForegroundFunc {
+		return f
+	}
+}
+
+// pixelColours returns a function giving the colour of a pixel of img, to be passed to a ForegroundFunc.
+// Pixels of paletted images are returned as PaletteColours (one per palette entry, so none are allocated
+// per pixel).
+func pixelColours(img image.Image) func(x int, y int) color.Color {
+	paletted, ok := img.(*image.Paletted)
+	if !ok || len(paletted.Palette) == 0 {
+		return img.At
+	}
+
+	colours := make([]color.Color, len(paletted.Palette))
+	for i, c := range paletted.Palette {
+		colours[i] = PaletteColour{Color: c, Index: uint8(i)}
+	}
+	return func(x int, y int) color.Color {
+		return colours[paletted.ColorIndexAt(x, y)]
+	}
+}
+
+// isNonBlack determines if a colour is treated as content (ie not black)
+func isNonBlack(c color.Color) bool {
+	r, g, b, _ := c.RGBA()
+	return !(r == 0 && g == 0 && b == 0)
+}
+
+// luminance returns the 8 bit luminance of a colour.
+func luminance(c color.Color) uint8 {
+	return color.GrayModel.Convert(c).(color.Gray).Y
+}
+
+// otsuThreshold calculates the luminance threshold that maximises the between class variance of the image.
+func otsuThreshold(img image.Image) uint8 {
+	histogram := make([]int, 256)
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			histogram[luminance(img.At(x, y))]++
+		}
+	}
+
+	total := bounds.Dx() * bounds.Dy()
+	sum := 0.0
+	for i, count := range histogram {
+		sum += float64(i * count)
+	}
+
+	sumBackground := 0.0
+	weightBackground := 0
+	maxVariance := 0.0
+	threshold := 0
+	for i, count := range histogram {
+		weightBackground += count
+		if weightBackground == 0 {
+			continue
+		}
+
+		weightForeground := total - weightBackground
+		if weightForeground == 0 {
+			break
+		}
+
+		sumBackground += float64(i * count)
+		meanBackground := sumBackground / float64(weightBackground)
+		meanForeground := (sum - sumBackground) / float64(weightForeground)
+		diff := meanBackground - meanForeground
+		variance := float64(weightBackground) * float64(weightForeground) * diff * diff
+		if variance > maxVariance {
+			maxVariance = variance
+			threshold = i
+		}
+	}
+
+	return uint8(threshold)
+}
@@ -0,0 +1,196 @@
+package border
+
+import (
+	"image"
+	"image/color"
+	"slices"
+	"testing"
+)
+
+// TestClassifiers tests each classifier against a set of colours.
+func TestClassifiers(t *testing.T) {
+
+	palette := color.Palette{color.Black, color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255}}
+	paletted := image.NewPaletted(image.Rect(0, 0, 1, 1), palette)
+
+	testCases := []struct {
+		name       string
+		classifier Classifier
+		img        image.Image
+		colours    []color.Color
+		expected   []bool
+	}{
+		{
+			name:       "non black",
+			classifier: NewNonBlackClassifier(),
+			colours:    []color.Color{color.Black, color.RGBA{1, 0, 0, 255}, color.White},
+			expected:   []bool{false, true, true},
+		},
+		{
+			name:       "luminance threshold",
+			classifier: NewLuminanceClassifier(127),
+			colours:    []color.Color{color.Gray{Y: 5}, color.Gray{Y: 127}, color.Gray{Y: 128}, color.White},
+			expected:   []bool{false, false, true, true},
+		},
+		{
+			name:       "alpha threshold",
+			classifier: NewAlphaClassifier(0),
+			colours:    []color.Color{color.Transparent, color.NRGBA{0, 0, 0, 255}, color.NRGBA{0, 0, 0, 10}},
+			expected:   []bool{false, true, true},
+		},
+		{
+			name:       "colour match",
+			classifier: NewColourClassifier(color.RGBA{255, 0, 0, 255}, color.RGBA{0, 255, 0, 255}),
+			colours:    []color.Color{color.RGBA{255, 0, 0, 255}, color.RGBA{0, 255, 0, 255}, color.RGBA{0, 0, 255, 255}},
+			expected:   []bool{true, true, false},
+		},
+		{
+			name:       "palette index match",
+			classifier: NewPaletteIndexClassifier(2),
+			img:        paletted,
+			colours:    palette,
+			expected:   []bool{false, false, true},
+		},
+		{
+			name:       "palette index match non paletted image",
+			classifier: NewPaletteIndexClassifier(2),
+			img:        image.NewRGBA(image.Rect(0, 0, 1, 1)),
+			colours:    palette,
+			expected:   []bool{false, false, false},
+		},
+		{
+			name: "custom",
+			classifier: NewCustomClassifier(func(c color.Color) bool {
+				r, _, _, _ := c.RGBA()
+				return r > 0
+			}),
+			colours:  []color.Color{color.RGBA{255, 0, 0, 255}, color.RGBA{0, 255, 0, 255}},
+			expected: []bool{true, false},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			img := tc.img
+			if img == nil {
+				img = image.NewRGBA(image.Rect(0, 0, 1, 1))
+			}
+
+			isForeground := tc.classifier(img)
+			for i, c := range tc.colours {
+				if isForeground(c) != tc.expected[i] {
+					t.Errorf("colour %d (%v): expected %v, got %v", i, c, tc.expected[i], isForeground(c))
+				}
+			}
+		})
+	}
+}
+
+// TestPaletteIndexClassifierDuplicateColours tests palette indices are matched by the index of each pixel rather
+// than by colour, as index masks often use the same colour for several classes.
+func TestPaletteIndexClassifierDuplicateColours(t *testing.T) {
+
+	// indices 1 and 2 are both white.
+	palette := color.Palette{color.Black, color.White, color.White, color.RGBA{255, 0, 0, 255}}
+	img := image.NewPaletted(image.Rect(0, 0, 4, 4), palette)
+	img.SetColorIndex(1, 1, 1)
+	img.SetColorIndex(2, 2, 2)
+	img.SetColorIndex(3, 0, 2)
+	img.SetColorIndex(0, 3, 3)
+
+	testCases := []struct {
+		name     string
+		indices  []uint8
+		padded   bool
+		expected []image.Point
+	}{
+		{name: "success first duplicate", indices: []uint8{1}, expected: []image.Point{{1, 1}}},
+		{name: "success second duplicate", indices: []uint8{2}, padded: true, expected: []image.Point{{2, 2}, {3, 0}}},
+		{name: "success both duplicates", indices: []uint8{1, 2}, padded: true, expected: []image.Point{{1, 1}, {2, 2}, {3, 0}}},
+		{name: "success unique colour", indices: []uint8{3}, padded: true, expected: []image.Point{{0, 3}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			si, err := LoadFromImage(img, LoadOptions{Classifier: NewPaletteIndexClassifier(tc.indices...)})
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if si.HasPadding() != tc.padded {
+				t.Fatalf("expected padding %v, got %v", tc.padded, si.HasPadding())
+			}
+
+			offset := 0
+			if tc.padded {
+				offset = 1
+			}
+			for y := 0; y < 4; y++ {
+				for x := 0; x < 4; x++ {
+					expected := 0
+					if slices.Contains(tc.expected, image.Point{x, y}) {
+						expected = 1
+					}
+					if got := si.GetXY(x+offset, y+offset); got != expected {
+						t.Errorf("pixel %d,%d: expected %d, got %d", x, y, expected, got)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestOtsuClassifier tests the threshold is placed between two groups of luminance values.
+func TestOtsuClassifier(t *testing.T) {
+
+	img := image.NewGray(image.Rect(0, 0, 4, 4))
+	for i := range img.Pix {
+		img.Pix[i] = 20
+	}
+	img.Pix[5] = 180
+	img.Pix[6] = 200
+
+	threshold := otsuThreshold(img)
+	if threshold < 20 || threshold >= 180 {
+		t.Errorf("expected threshold between 20 and 180, got %d", threshold)
+	}
+
+	si, err := LoadFromImage(img, LoadOptions{Classifier: NewOtsuClassifier()})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []int{0, 0, 0, 0, 0, 1, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+	if slices.Compare(si.GetAllData(), expected) != 0 {
+		t.Errorf("expected data %v, got %v", expected, si.GetAllData())
+	}
+}
+
+// TestLoadFromImageWithClassifier checks the classifier is also used when determining padding.
+func TestLoadFromImageWithClassifier(t *testing.T) {
+
+	// fully transparent except for two opaque black pixels, one on the edge.
+	img := image.NewNRGBA(image.Rect(0, 0, 3, 3))
+	img.SetNRGBA(0, 0, color.NRGBA{0, 0, 0, 255})
+	img.SetNRGBA(1, 1, color.NRGBA{0, 0, 0, 255})
+
+	si, err := LoadFromImage(img, LoadOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if si.HasPadding() {
+		t.Errorf("expected no padding with default classifier")
+	}
+
+	si, err = LoadFromImage(img, LoadOptions{Classifier: NewAlphaClassifier(127)})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !si.HasPadding() {
+		t.Errorf("expected padding with alpha classifier")
+	}
+
+	expected := []int{0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+	if slices.Compare(si.GetAllData(), expected) != 0 {
+		t.Errorf("expected data %v, got %v", expected, si.GetAllData())
+	}
+}
@@ -0,0 +1,43 @@
+package border
+
+import (
+	"image"
+
+	image2 "github.com/kpfaulkner/borders/image"
+)
+
+// ComponentContourIds maps each component label (as generated by image.LabelComponents) to the Id of the outer
+// Contour around it. The labels and contours must be generated from the same image with the same connectivity.
+//
+// Each outer border starts at the first pixel of its component (scanning row by row), which is kept regardless of
+// FindOptions, so the label is read from there.
+func ComponentContourIds(labels *image2.Labels, root *Contour) map[int]int {
+	ids := make(map[int]int)
+	var walk func(c *Contour)
+	walk = func(c *Contour) {
+		if c.BorderType == Outer {
+			start, ok := contourStart(c)
+			if ok && start.X >= 0 && start.Y >= 0 && start.X < labels.Width && start.Y < labels.Height {
+				if label := labels.At(start.X, start.Y); label != 0 {
+					ids[label] = c.Id
+				}
+			}
+		}
+		for _, child := range c.Children {
+			walk(child)
+		}
+	}
+	walk(root)
+	return ids
+}
+
+// contourStart returns the first point of the contour, from the ChainCode if it is encoded.
+func contourStart(c *Contour) (image.Point, bool) {
+	if c.ChainCode != nil {
+		return c.ChainCode.Start, true
+	}
+	if len(c.Points) == 0 {
+		return image.Point{}, false
+	}
+	return c.Points[0], true
+}
@@ -0,0 +1,68 @@
+package border
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kpfaulkner/borders/common"
+	image2 "github.com/kpfaulkner/borders/image"
+)
+
+// TestComponentContourIds tests every component maps to the outer contour around it.
+func TestComponentContourIds(t *testing.T) {
+	testCases := []struct {
+		name         string
+		connectivity common.Connectivity
+		opts         FindOptions
+	}{
+		{name: "8 connected", connectivity: common.Connectivity8},
+		{name: "4 connected", connectivity: common.Connectivity4, opts: FindOptions{Connectivity: common.Connectivity4}},
+		{name: "chain code", connectivity: common.Connectivity8, opts: FindOptions{ChainCode: true}},
+		{name: "pixel edges simplified", connectivity: common.Connectivity8, opts: FindOptions{PixelEdges: true, Approximation: ApproxSimple}},
+	}
+
+	for _, filename := range []string{`../testimages/unittest1.png`, `../testimages/image1.png`, `../testimages/florida.png`} {
+		for _, tc := range testCases {
+			t.Run(filename+" "+tc.name, func(t *testing.T) {
+				img, err := LoadImage(filename, 0, 0)
+				if err != nil {
+					t.Fatalf("Unable to load test image: %s", err.Error())
+				}
+
+				labels := image2.LabelComponents(img, tc.connectivity)
+				root, err := FindContoursCtx(context.Background(), img.Clone(), tc.opts)
+				if err != nil {
+					t.Fatalf("Unable to find contours: %s", err.Error())
+				}
+				expected, err := FindContoursCtx(context.Background(), img, FindOptions{Connectivity: tc.connectivity})
+				if err != nil {
+					t.Fatalf("Unable to find contours: %s", err.Error())
+				}
+
+				ids := ComponentContourIds(labels, root)
+				if len(ids) != len(labels.Stats) {
+					t.Fatalf("expected %d components, got %d", len(labels.Stats), len(ids))
+				}
+
+				contours := make(map[int]*Contour)
+				for _, c := range flattenContours(expected) {
+					contours[c.Id] = c
+				}
+
+				previous := 0
+				for _, stats := range labels.Stats {
+					id := ids[stats.Label]
+					if id <= previous {
+						t.Errorf("label %d: expected contour Ids in the same order as labels, got %d after %d", stats.Label, id, previous)
+					}
+					previous = id
+
+					c := contours[id]
+					if c == nil || c.BorderType != Outer || c.Bounds() != stats.Bounds {
+						t.Errorf("label %d: expected outer contour with bounds %v", stats.Label, stats.Bounds)
+					}
+				}
+			})
+		}
+	}
+}
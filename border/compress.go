@@ -0,0 +1,201 @@
+package border
+
+import (
+	"errors"
+	"image"
+	"math"
+)
+
+// Approximation determines which points of each border are kept while tracing.
+type Approximation int
+
+const (
+	// ApproxNone keeps every border pixel.
+	ApproxNone Approximation = iota
+
+	// ApproxSimple only keeps the points where the direction changes, so straight horizontal, vertical and
+	// diagonal runs are reduced to their end points (as per OpenCV's CHAIN_APPROX_SIMPLE).
+	ApproxSimple
+
+	// ApproxTC89L1 keeps the dominant points found by the Teh-Chin algorithm using the 1-curvature
+	// (change of direction) as the measure of significance (as per OpenCV's CHAIN_APPROX_TC89_L1).
+	ApproxTC89L1
+
+	// ApproxTC89KCOS keeps the dominant points found by the Teh-Chin algorithm using the k-cosine
+	// as the measure of significance (as per OpenCV's CHAIN_APPROX_TC89_KCOS).
+	ApproxTC89KCOS
+)
+
+var (
+	ErrApproxChainCode = errors.New("chain codes require every point, so can't be used with an approximation")
+)
+
+// approximateBorder reduces the points of a traced border. The first point is always kept, as it is the
+// point the border was found from.
+func approximateBorder(points []image.Point, approx Approximation) []image.Point {
+	if len(points) <= 3 {
+		return points
+	}
+
+	switch approx {
+	case ApproxSimple:
+		return approxSimple(points)
+	case ApproxTC89L1, ApproxTC89KCOS:
+		return approxTehChin(points, approx)
+	}
+	return points
+}
+
+// approxSimple keeps the first point and every point where the direction changes.
+func approxSimple(points []image.Point) []image.Point {
+	n := len(points)
+	simplified := []image.Point{points[0]}
+	for i := 1; i < n; i++ {
+		in := points[i].Sub(points[i-1])
+		out := points[(i+1)%n].Sub(points[i])
+		if in != out {
+			simplified = append(simplified, points[i])
+		}
+	}
+	return simplified
+}
+
+// appendPoint appends p to the border being traced. When compressing (ApproxSimple), a point continuing in the
+// same direction as the last step replaces the last point rather than being appended, so only the end points
+// of each straight run are held. The first point is always kept.
+func appendPoint(border []image.Point, p image.Point, compress bool) []image.Point {
+	if n := len(border); compress && n >= 2 && step(border[n-1].Sub(border[n-2])) == p.Sub(border[n-1]) {
+		border[n-1] = p
+		return border
+	}
+	return append(border, p)
+}
+
+// closeBorder drops the last point of a compressed border if it lies on the straight run back to the first point.
+func closeBorder(border []image.Point, compress bool) []image.Point {
+	if n := len(border); compress && n >= 3 && step(border[n-1].Sub(border[n-2])) == border[0].Sub(border[n-1]) {
+		return border[:n-1]
+	}
+	return border
+}
+
+// step returns the unit step (in each axis) in the direction of d.
+func step(d image.Point) image.Point {
+	return image.Point{sign(d.X), sign(d.Y)}
+}
+
+// sign returns -1, 0 or 1 depending on the sign of v.
+func sign(v int) int {
+	switch {
+	case v < 0:
+		return -1
+	case v > 0:
+		return 1
+	}
+	return 0
+}
+
+// approxTehChin keeps the dominant points of the border, as described in "On the Detection of Dominant Points
+// on Digital Curves" (Teh and Chin, 1989).
+func approxTehChin(points []image.Point, approx Approximation) []image.Point {
+	n := len(points)
+	at := func(i int) image.Point {
+		return points[((i%n)+n)%n]
+	}
+
+	// step 1: determine the region of support for each point. This is the largest k where the chord between
+	// the points k either side is still getting longer, and the distance of the point from the chord
+	// (relative to the chord length) is still increasing.
+	support := make([]int, n)
+	for i := range points {
+		k := 1
+		for ; k < n/2-1; k++ {
+			l1, d1 := chord(at(i-k), at(i), at(i+k))
+			l2, d2 := chord(at(i-k-1), at(i), at(i+k+1))
+			if l1 >= l2 {
+				break
+			}
+			if d1 > 0 && d1/l1 >= d2/l2 || d1 < 0 && d1/l1 <= d2/l2 {
+				break
+			}
+		}
+		support[i] = k
+	}
+
+	// step 2: measure the significance (curvature) of each point.
+	significance := make([]float64, n)
+	for i := range points {
+		switch approx {
+		case ApproxTC89L1:
+			in := at(i).Sub(at(i - 1))
+			out := at(i + 1).Sub(at(i))
+			significance[i] = float64(abs(out.X-in.X) + abs(out.Y-in.Y))
+		default:
+			k := support[i]
+			a := at(i - k).Sub(at(i))
+			b := at(i + k).Sub(at(i))
+			cos := float64(a.X*b.X+a.Y*b.Y) / (math.Hypot(float64(a.X), float64(a.Y)) * math.Hypot(float64(b.X), float64(b.Y)))
+			significance[i] = cos + 1
+		}
+	}
+
+	// step 3: non-maximum suppression, only keep points that are the most significant within their region of
+	// support. Points with no curvature (straight runs for the 1-curvature) are never kept.
+	keep := make([]bool, n)
+	for i := range points {
+		if significance[i] <= 0 {
+			continue
+		}
+
+		keep[i] = true
+		for j := 1; j <= support[i]/2; j++ {
+			if significance[((i-j)%n+n)%n] > significance[i] || significance[(i+j)%n] > significance[i] {
+				keep[i] = false
+				break
+			}
+		}
+	}
+
+	// step 4: where neighbouring points both have a region of support of 1, only keep the more significant.
+	for i := range points {
+		next := (i + 1) % n
+		if keep[i] && keep[next] && support[i] == 1 && support[next] == 1 {
+			if significance[i] < significance[next] {
+				keep[i] = false
+			} else {
+				keep[next] = false
+			}
+		}
+	}
+
+	keep[0] = true
+	simplified := []image.Point{}
+	for i, p := range points {
+		if keep[i] {
+			simplified = append(simplified, p)
+		}
+	}
+
+	// too few points to form a polygon, fall back to only removing straight runs.
+	if len(simplified) < 3 {
+		return approxSimple(points)
+	}
+	return simplified
+}
+
+// chord returns the length of the chord from a to b and the signed distance of p from it.
+func chord(a image.Point, p image.Point, b image.Point) (float64, float64) {
+	l := math.Hypot(float64(b.X-a.X), float64(b.Y-a.Y))
+	if l == 0 {
+		return 0, 0
+	}
+	return l, float64(cross(a, b, p)) / l
+}
+
+// abs returns the absolute value of an int.
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,190 @@
+package border
+
+import (
+	"context"
+	"errors"
+	"image"
+	"slices"
+	"testing"
+
+	"github.com/kpfaulkner/borders/common"
+)
+
+// TestApproximateBorder tests the points kept for simple shapes.
+func TestApproximateBorder(t *testing.T) {
+
+	// 4x4 square traced anti-clockwise from the top left.
+	square := []image.Point{{0, 0}, {0, 1}, {0, 2}, {0, 3}, {1, 3}, {2, 3}, {3, 3}, {3, 2}, {3, 1}, {3, 0}, {2, 0}, {1, 0}}
+
+	// diamond with diagonal sides, starting from the top.
+	diamond := []image.Point{{3, 0}, {2, 1}, {1, 2}, {0, 3}, {1, 4}, {2, 5}, {3, 6}, {4, 5}, {5, 4}, {6, 3}, {5, 2}, {4, 1}}
+
+	// square starting part way along a side.
+	offsetStart := append(slices.Clone(square[2:]), square[:2]...)
+
+	squareCorners := []image.Point{{0, 0}, {0, 3}, {3, 3}, {3, 0}}
+	diamondCorners := []image.Point{{3, 0}, {0, 3}, {3, 6}, {6, 3}}
+
+	testCases := []struct {
+		name     string
+		points   []image.Point
+		approx   Approximation
+		expected []image.Point
+	}{
+		{name: "none", points: square, approx: ApproxNone, expected: square},
+		{name: "simple square", points: square, approx: ApproxSimple, expected: squareCorners},
+		{name: "simple diamond", points: diamond, approx: ApproxSimple, expected: diamondCorners},
+		{name: "simple keeps start", points: offsetStart, approx: ApproxSimple, expected: []image.Point{{0, 2}, {0, 3}, {3, 3}, {3, 0}, {0, 0}}},
+		{name: "simple too few points", points: square[:3], approx: ApproxSimple, expected: square[:3]},
+		{name: "tc89 l1 square", points: square, approx: ApproxTC89L1, expected: squareCorners},
+		{name: "tc89 kcos square", points: square, approx: ApproxTC89KCOS, expected: squareCorners},
+		{name: "tc89 l1 diamond", points: diamond, approx: ApproxTC89L1, expected: diamondCorners},
+		{name: "tc89 kcos diamond", points: diamond, approx: ApproxTC89KCOS, expected: diamondCorners},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			points := approximateBorder(tc.points, tc.approx)
+			if !slices.Equal(points, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, points)
+			}
+		})
+	}
+}
+
+// TestFindContoursApproximation tests approximated contours are a subset of the full contours, in the same
+// order and starting from the same point.
+func TestFindContoursApproximation(t *testing.T) {
+	testCases := []struct {
+		name          string
+		approx        Approximation
+		chainCode     bool
+		maxPointRatio float64
+		expectErr     error
+	}{
+		{name: "simple", approx: ApproxSimple, maxPointRatio: 0.5},
+		{name: "tc89 l1", approx: ApproxTC89L1, maxPointRatio: 0.5},
+		{name: "tc89 kcos", approx: ApproxTC89KCOS, maxPointRatio: 0.5},
+		{name: "error with chain code", approx: ApproxSimple, chainCode: true, expectErr: ErrApproxChainCode},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			img, err := LoadImage(`../testimages/image1.png`, 0, 0)
+			if err != nil {
+				t.Fatalf("Unable to load test image: %s", err.Error())
+			}
+
+			expected, err := FindContours(img.Clone())
+			if err != nil {
+				t.Fatalf("Unable to find contours: %s", err.Error())
+			}
+
+			approximated, err := FindContoursCtx(context.Background(), img, FindOptions{Approximation: tc.approx, ChainCode: tc.chainCode})
+			if tc.expectErr != nil {
+				if !errors.Is(err, tc.expectErr) {
+					t.Errorf("expected error %v, got %v", tc.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unable to find contours: %s", err.Error())
+			}
+
+			full := flattenContours(expected)
+			approx := flattenContours(approximated)
+			if len(full) != len(approx) {
+				t.Fatalf("expected %d contours, got %d", len(full), len(approx))
+			}
+
+			fullPoints := 0
+			approxPoints := 0
+			for i := range full {
+				fullPoints += len(full[i].Points)
+				approxPoints += len(approx[i].Points)
+				if full[i].Id != approx[i].Id || full[i].ParentId != approx[i].ParentId {
+					t.Errorf("expected contour %d with parent %d, got %d with parent %d", full[i].Id, full[i].ParentId, approx[i].Id, approx[i].ParentId)
+				}
+				if len(full[i].Points) > 0 && (len(approx[i].Points) == 0 || approx[i].Points[0] != full[i].Points[0]) {
+					t.Errorf("contour %d: expected to start at the same point", full[i].Id)
+				}
+				if !isSubsequence(approx[i].Points, full[i].Points) {
+					t.Errorf("contour %d: expected points to be a subset of the full contour in the same order", full[i].Id)
+				}
+			}
+
+			if float64(approxPoints) > float64(fullPoints)*tc.maxPointRatio {
+				t.Errorf("expected at most %.0f%% of the %d points, got %d", tc.maxPointRatio*100, fullPoints, approxPoints)
+			}
+		})
+	}
+}
+
+// flattenContours returns every contour in the tree, depth first.
+func flattenContours(c *Contour) []*Contour {
+	contours := []*Contour{c}
+	for _, child := range c.Children {
+		contours = append(contours, flattenContours(child)...)
+	}
+	return contours
+}
+
+// isSubsequence checks every point of sub appears in points, in the same order.
+func isSubsequence(sub []image.Point, points []image.Point) bool {
+	i := 0
+	for _, p := range points {
+		if i < len(sub) && sub[i] == p {
+			i++
+		}
+	}
+	return i == len(sub)
+}
+
+// TestFindContoursSimpleWhileTracing checks compressing while tracing gives the same points as compressing the
+// complete border afterwards.
+func TestFindContoursSimpleWhileTracing(t *testing.T) {
+	testCases := []struct {
+		name         string
+		filename     string
+		pixelEdges   bool
+		connectivity common.Connectivity
+	}{
+		{name: "success with nested contours", filename: `../testimages/unittest1.png`},
+		{name: "success with many contours", filename: `../testimages/florida.png`},
+		{name: "success with 4 connectivity", filename: `../testimages/image1.png`, connectivity: common.Connectivity4},
+		{name: "success with pixel edges", filename: `../testimages/image1.png`, pixelEdges: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			img, err := LoadImage(tc.filename, 0, 0)
+			if err != nil {
+				t.Fatalf("Unable to load test image: %s", err.Error())
+			}
+
+			opts := FindOptions{PixelEdges: tc.pixelEdges, Connectivity: tc.connectivity}
+			expected, err := FindContoursCtx(context.Background(), img.Clone(), opts)
+			if err != nil {
+				t.Fatalf("Unable to find contours: %s", err.Error())
+			}
+
+			opts.Approximation = ApproxSimple
+			compressed, err := FindContoursCtx(context.Background(), img, opts)
+			if err != nil {
+				t.Fatalf("Unable to find contours: %s", err.Error())
+			}
+
+			full := flattenContours(expected)
+			approx := flattenContours(compressed)
+			if len(full) != len(approx) {
+				t.Fatalf("expected %d contours, got %d", len(full), len(approx))
+			}
+			for i := range full {
+				want := approximateBorder(full[i].Points, ApproxSimple)
+				if !slices.Equal(approx[i].Points, want) {
+					t.Fatalf("contour %d: expected %v, got %v", full[i].Id, want, approx[i].Points)
+				}
+			}
+		})
+	}
+}
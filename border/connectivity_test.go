@@ -0,0 +1,168 @@
+package border
+
+import (
+	"context"
+	"image"
+	"slices"
+	"testing"
+
+	"github.com/kpfaulkner/borders/common"
+)
+
+// TestFindContoursConnectivity tests pixels touching diagonally are only connected with 8 connectivity.
+func TestFindContoursConnectivity(t *testing.T) {
+	testCases := []struct {
+		name           string
+		rows           []string
+		connectivity   common.Connectivity
+		expectedTypes  []int
+		expectedParent []int
+		expectedPoints [][]image.Point
+	}{
+		{
+			name:           "8 connected squares touching diagonally",
+			rows:           []string{"......", ".##...", ".##...", "...##.", "...##.", "......"},
+			connectivity:   common.Connectivity8,
+			expectedTypes:  []int{Outer},
+			expectedParent: []int{1},
+			expectedPoints: [][]image.Point{{{1, 1}, {1, 2}, {2, 2}, {3, 3}, {3, 4}, {4, 4}, {4, 3}, {3, 3}, {2, 2}, {2, 1}}},
+		},
+		{
+			name:           "4 connected squares touching diagonally",
+			rows:           []string{"......", ".##...", ".##...", "...##.", "...##.", "......"},
+			connectivity:   common.Connectivity4,
+			expectedTypes:  []int{Outer, Outer},
+			expectedParent: []int{1, 1},
+			expectedPoints: [][]image.Point{{{1, 1}, {1, 2}, {2, 2}, {2, 1}}, {{3, 3}, {3, 4}, {4, 4}, {4, 3}}},
+		},
+		{
+			name:           "8 connected ring with diagonal gap",
+			rows:           []string{".....", ".##..", ".#.#.", "..##.", "....."},
+			connectivity:   common.Connectivity8,
+			expectedTypes:  []int{Outer, Hole},
+			expectedParent: []int{1, 2},
+			expectedPoints: [][]image.Point{
+				{{1, 1}, {1, 2}, {2, 3}, {3, 3}, {3, 2}, {2, 1}},
+				{{1, 2}, {2, 1}, {3, 2}, {2, 3}},
+			},
+		},
+		{
+			name:           "4 connected ring with diagonal gap",
+			rows:           []string{".....", ".##..", ".#.#.", "..##.", "....."},
+			connectivity:   common.Connectivity4,
+			expectedTypes:  []int{Outer, Outer},
+			expectedParent: []int{1, 1},
+			expectedPoints: [][]image.Point{{{1, 1}, {1, 2}, {1, 1}, {2, 1}}, {{3, 2}, {3, 3}, {2, 3}, {3, 3}}},
+		},
+		{
+			name:           "4 connected diamond",
+			rows:           []string{".....", "..#..", ".#.#.", "..#..", "....."},
+			connectivity:   common.Connectivity4,
+			expectedTypes:  []int{Outer, Outer, Outer, Outer},
+			expectedParent: []int{1, 1, 1, 1},
+			expectedPoints: [][]image.Point{{{2, 1}}, {{1, 2}}, {{3, 2}}, {{2, 3}}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			root, err := FindContoursCtx(context.Background(), createRowsSuzukiImage(tc.rows), FindOptions{Connectivity: tc.connectivity})
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			contours := flattenContours(root)[1:]
+			if len(contours) != len(tc.expectedTypes) {
+				t.Fatalf("expected %d contours, got %d", len(tc.expectedTypes), len(contours))
+			}
+			for i, c := range contours {
+				if c.BorderType != tc.expectedTypes[i] || c.ParentId != tc.expectedParent[i] {
+					t.Errorf("contour %d: expected type %d parent %d, got type %d parent %d", c.Id, tc.expectedTypes[i], tc.expectedParent[i], c.BorderType, c.ParentId)
+				}
+				if !slices.Equal(c.Points, tc.expectedPoints[i]) {
+					t.Errorf("contour %d: expected points %v, got %v", c.Id, tc.expectedPoints[i], c.Points)
+				}
+			}
+		})
+	}
+}
+
+// TestFindContoursConnectivityComponents tests there is an outer border for every foreground component, and a
+// hole for every background component other than the outside (using the opposite connectivity).
+func TestFindContoursConnectivityComponents(t *testing.T) {
+	testCases := []struct {
+		name         string
+		connectivity common.Connectivity
+		foreground   []image.Point
+		background   []image.Point
+	}{
+		{name: "8 connected", connectivity: common.Connectivity8, foreground: dirDelta, background: dirDelta4},
+		{name: "4 connected", connectivity: common.Connectivity4, foreground: dirDelta4, background: dirDelta},
+	}
+
+	for _, filename := range []string{`../testimages/unittest1.png`, `../testimages/image1.png`, `../testimages/florida.png`} {
+		for _, tc := range testCases {
+			t.Run(filename+" "+tc.name, func(t *testing.T) {
+				img, err := LoadImage(filename, 0, 0)
+				if err != nil {
+					t.Fatalf("Unable to load test image: %s", err.Error())
+				}
+
+				pixels := 0
+				for _, v := range img.GetAllData() {
+					if v != 0 {
+						pixels++
+					}
+				}
+				fgCount := countComponents(img, true, tc.foreground)
+				bgCount := countComponents(img, false, tc.background)
+
+				root, err := FindContoursCtx(context.Background(), img, FindOptions{Connectivity: tc.connectivity, PixelEdges: true})
+				if err != nil {
+					t.Fatalf("Unable to find contours: %s", err.Error())
+				}
+
+				outers, holes := 0, 0
+				area := 0.0
+				for _, c := range flattenContours(root)[1:] {
+					if c.BorderType == Outer {
+						outers++
+						area += c.NetArea()
+					} else {
+						holes++
+					}
+				}
+
+				if outers != fgCount || holes != bgCount-1 {
+					t.Errorf("expected %d outer borders and %d holes, got %d and %d", fgCount, bgCount-1, outers, holes)
+				}
+				if area != float64(pixels) {
+					t.Errorf("expected area %d, got %f", pixels, area)
+				}
+			})
+		}
+	}
+}
+
+// countComponents counts the connected areas of foreground (or background) in the image.
+func countComponents(img *common.SuzukiImage, foreground bool, neighbours []image.Point) int {
+	regions := make([]int32, img.Width*img.Height)
+	for i := range regions {
+		regions[i] = -1
+	}
+
+	include := func(x int, y int) bool {
+		return (img.GetXY(x, y) != 0) == foreground
+	}
+
+	count := 0
+	for idx := range regions {
+		if regions[idx] != -1 || !include(idx%img.Width, idx/img.Width) {
+			continue
+		}
+		count++
+		regions[idx] = int32(count)
+		floodFill(img, regions, []int{idx}, int32(count), neighbours, include)
+	}
+	return count
+}
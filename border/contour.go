@@ -2,9 +2,7 @@ package border
 
 import (
 	"image"
-	"strings"
-
-	log "github.com/sirupsen/logrus"
+	"log/slog"
 )
 
 const (
@@ -12,6 +10,12 @@ const (
 	Hole  = 1
 )
 
+// PointF is a point with floating point co-ords, for values (such as centres) that don't fall on a pixel.
+type PointF struct {
+	X float64
+	Y float64
+}
+
 // Contour represents a single contour/border extracted from an image.
 // It also tracks its parents and children.
 type Contour struct {
@@ -19,6 +23,10 @@ type Contour struct {
 	// Points making up the contour
 	Points []image.Point
 
+	// ChainCode is set instead of Points when the contour was found with FindOptions.ChainCode (or converted
+	// with EncodeChainCodes). The other Contour methods use Points, so call DecodeChainCodes before using them.
+	ChainCode *ChainCode
+
 	Id int
 
 	// Outer or Hole.
@@ -71,30 +79,47 @@ func (c *Contour) GetAllPoints() []image.Point {
 	return allPoints
 }
 
-// ContourStats generates writes the stats to a log about the contour and all children.
-// Primarily used for debugging
-func ContourStats(c *Contour, offset int) {
+// ContourStats writes debug level stats about the contour and all children to logger.
+// Primarily used for debugging. A nil logger writes nothing.
+func ContourStats(c *Contour, offset int, logger *slog.Logger) {
+	if logger == nil {
+		return
+	}
+
 	if len(c.Points) > 0 {
-		pad := strings.Repeat(" ", offset)
-		log.Debugf("%s%d : len %d :  no kids %d : no col %d : col with parent %+v\n", pad, c.Id, len(c.Points), len(c.Children), len(c.ConflictingContours), c.ParentCollision)
+		logContourStats(c, offset, logger)
 	}
 
 	for _, ch := range c.Children {
-		ContourStats(ch, offset+2)
+		ContourStats(ch, offset+2, logger)
 	}
 }
 
-// ContourStatsWithCollisions generates writes to stdout stats about the contour and all children that have collisions
-// Primarily used for debugging.
-func ContourStatsWithCollisions(c *Contour, offset int) {
+// ContourStatsWithCollisions writes debug level stats about the contour and all children that have collisions
+// to logger. Primarily used for debugging. A nil logger writes nothing.
+func ContourStatsWithCollisions(c *Contour, offset int, logger *slog.Logger) {
+	if logger == nil {
+		return
+	}
+
 	if len(c.Points) > 0 {
 		if len(c.ConflictingContours) > 0 {
-			pad := strings.Repeat(" ", offset)
-			log.Debugf("%s%d : len %d :  no kids %d : no col %d : col with parent %+v\n", pad, c.Id, len(c.Points), len(c.Children), len(c.ConflictingContours), c.ParentCollision)
+			logContourStats(c, offset, logger)
 		}
 	}
 
 	for _, ch := range c.Children {
-		ContourStatsWithCollisions(ch, offset+2)
+		ContourStatsWithCollisions(ch, offset+2, logger)
 	}
 }
+
+// logContourStats logs the stats for a single contour. offset is included to show the tree structure.
+func logContourStats(c *Contour, offset int, logger *slog.Logger) {
+	logger.Debug("contour stats",
+		"offset", offset,
+		"id", c.Id,
+		"points", len(c.Points),
+		"children", len(c.Children),
+		"collisions", len(c.ConflictingContours),
+		"parentCollision", c.ParentCollision)
+}
@@ -0,0 +1,60 @@
+package border
+
+import (
+	"image"
+
+	"github.com/kpfaulkner/borders/common"
+)
+
+// crackDelta is the direction moved along the pixel edges (N, E, S, W). Turning right is the next index.
+var crackDelta = []image.Point{{0, -1}, {1, 0}, {0, 1}, {-1, 0}}
+
+// crackBorder follows the pixel edges of the border starting at pixel p0, returning the pixel corners.
+// The corner x,y is the top left of pixel x,y. Outer borders start on the left edge of p0 and holes on the right,
+// matching where FindContours detects them.
+//
+// The edges are followed with the foreground on the left, so outer borders are anti-clockwise when displayed and
+// holes clockwise (as per createBorder). Where pixels only meet at a corner, the foreground (8 connectivity) or
+// background (4 connectivity) is treated as connected, so the border touches itself at that corner.
+// If compress is set only the corners where the direction changes are kept (ApproxSimple).
+func crackBorder(img *common.SuzukiImage, p0 image.Point, isOuter bool, connectivity common.Connectivity, compress bool) []image.Point {
+	start := p0
+	dir := 2
+	if !isOuter {
+		start = p0.Add(image.Point{1, 1})
+		dir = 0
+	}
+
+	isForeground := func(p image.Point) bool {
+		if p.X < 0 || p.Y < 0 || p.X >= img.Width || p.Y >= img.Height {
+			return false
+		}
+		return img.Get(p) != 0
+	}
+
+	border := []image.Point{}
+	corner := start
+	startDir := dir
+	for {
+		border = appendPoint(border, corner, compress)
+		d := crackDelta[dir]
+		corner = corner.Add(d)
+
+		// pixels either side of the edge ahead of the corner.
+		left := image.Point{d.Y, -d.X}
+		aheadLeft := corner.Add(d.Add(left).Sub(image.Point{1, 1}).Div(2))
+		aheadRight := corner.Add(d.Sub(left).Sub(image.Point{1, 1}).Div(2))
+		right := isForeground(aheadRight)
+		straight := isForeground(aheadLeft)
+		if right && (straight || connectivity != common.Connectivity4) {
+			dir = (dir + 1) % 4
+		} else if !straight {
+			dir = (dir + 3) % 4
+		}
+
+		if corner == start && dir == startDir {
+			break
+		}
+	}
+	return closeBorder(border, compress)
+}
@@ -0,0 +1,118 @@
+package border
+
+import (
+	"context"
+	"image"
+	"slices"
+	"testing"
+)
+
+// TestFindContoursPixelEdges tests borders are traced along the pixel edges.
+func TestFindContoursPixelEdges(t *testing.T) {
+	testCases := []struct {
+		name           string
+		rows           []string
+		expectedPoints [][]image.Point
+		expectedArea   float64
+	}{
+		{
+			name:           "success single pixel",
+			rows:           []string{"...", ".#.", "..."},
+			expectedPoints: [][]image.Point{{{1, 1}, {1, 2}, {2, 2}, {2, 1}}},
+			expectedArea:   1,
+		},
+		{
+			name:           "success single pixel wide line",
+			rows:           []string{".....", ".###.", "....."},
+			expectedPoints: [][]image.Point{{{1, 1}, {1, 2}, {2, 2}, {3, 2}, {4, 2}, {4, 1}, {3, 1}, {2, 1}}},
+			expectedArea:   3,
+		},
+		{
+			name: "success diagonal",
+			rows: []string{"....", ".#..", "..#.", "...."},
+			expectedPoints: [][]image.Point{
+				{{1, 1}, {1, 2}, {2, 2}, {2, 3}, {3, 3}, {3, 2}, {2, 2}, {2, 1}},
+			},
+			expectedArea: 2,
+		},
+		{
+			name: "success ring with hole",
+			rows: []string{".....", ".###.", ".#.#.", ".###.", "....."},
+			expectedPoints: [][]image.Point{
+				{{1, 1}, {1, 2}, {1, 3}, {1, 4}, {2, 4}, {3, 4}, {4, 4}, {4, 3}, {4, 2}, {4, 1}, {3, 1}, {2, 1}},
+				{{2, 3}, {2, 2}, {3, 2}, {3, 3}},
+			},
+			expectedArea: 8,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			root, err := FindContoursCtx(context.Background(), createRowsSuzukiImage(tc.rows), FindOptions{PixelEdges: true})
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			contours := flattenContours(root)[1:]
+			if len(contours) != len(tc.expectedPoints) {
+				t.Fatalf("expected %d contours, got %d", len(tc.expectedPoints), len(contours))
+			}
+			for i, c := range contours {
+				if !slices.Equal(c.Points, tc.expectedPoints[i]) {
+					t.Errorf("contour %d: expected points %v, got %v", c.Id, tc.expectedPoints[i], c.Points)
+				}
+			}
+
+			if area := root.Children[0].NetArea(); area != tc.expectedArea {
+				t.Errorf("expected area %f, got %f", tc.expectedArea, area)
+			}
+		})
+	}
+}
+
+// TestFindContoursPixelEdgesArea tests the area of the pixel edge contours is the number of foreground pixels,
+// with the same tree as FindContours.
+func TestFindContoursPixelEdgesArea(t *testing.T) {
+	for _, filename := range []string{`../testimages/unittest1.png`, `../testimages/image1.png`, `../testimages/florida.png`} {
+		for _, approx := range []Approximation{ApproxNone, ApproxSimple} {
+			t.Run(filename, func(t *testing.T) {
+				img, err := LoadImage(filename, 0, 0)
+				if err != nil {
+					t.Fatalf("Unable to load test image: %s", err.Error())
+				}
+
+				pixels := 0
+				for _, v := range img.GetAllData() {
+					if v != 0 {
+						pixels++
+					}
+				}
+
+				expected, err := FindContours(img.Clone())
+				if err != nil {
+					t.Fatalf("Unable to find contours: %s", err.Error())
+				}
+
+				root, err := FindContoursCtx(context.Background(), img, FindOptions{PixelEdges: true, Approximation: approx})
+				if err != nil {
+					t.Fatalf("Unable to find contours: %s", err.Error())
+				}
+
+				all := flattenContours(expected)
+				area := 0.0
+				for i, c := range flattenContours(root) {
+					if c.Id != all[i].Id || c.ParentId != all[i].ParentId || c.BorderType != all[i].BorderType {
+						t.Errorf("expected contour %d with parent %d, got %d with parent %d", all[i].Id, all[i].ParentId, c.Id, c.ParentId)
+					}
+					if c.BorderType == Outer {
+						area += c.NetArea()
+					}
+				}
+
+				if area != float64(pixels) {
+					t.Errorf("expected area %d, got %f", pixels, area)
+				}
+			})
+		}
+	}
+}
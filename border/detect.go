@@ -1,11 +1,11 @@
 package border
 
 import (
-	"errors"
+	"context"
 	"image"
+	"log/slog"
 
 	"github.com/kpfaulkner/borders/common"
-	log "github.com/sirupsen/logrus"
 )
 
 var (
@@ -14,14 +14,70 @@ var (
 	dirDelta = []image.Point{{0, -1}, {1, -1}, {1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}}
 )
 
+// FindOptions controls FindContoursCtx.
+type FindOptions struct {
+
+	// Progress, if set, is called after each row of the image is scanned.
+	Progress common.ProgressFunc
+
+	// Logger receives errors and debug information encountered while finding contours.
+	// If nil, nothing is logged.
+	Logger *slog.Logger
+
+	// ChainCode stores each contour as a ChainCode rather than Points, which uses a fraction of the memory.
+	// Can not be used with Approximation.
+	ChainCode bool
+
+	// Approximation determines which points of each border are kept. Defaults to ApproxNone (every pixel).
+	Approximation Approximation
+
+	// PixelEdges traces along the edges of the border pixels rather than through their centres, so Points are
+	// pixel corners (x,y being the top left of pixel x,y). Area and NetArea then give the exact number of pixels,
+	// and single pixel wide features are not reduced to lines. Of the approximations only ApproxSimple keeps the
+	// area exact.
+	PixelEdges bool
+
+	// Mode determines which contours are returned and how they are arranged. Defaults to RetrTree.
+	Mode Retrieval
+
+	// Connectivity of the foreground. Defaults to common.Connectivity8.
+	Connectivity common.Connectivity
+
+	// SkipFailedContours continues past contours that can not be traced rather than returning the error.
+	// Failed contours are kept in the tree (so their children are still found) with the points traced so far
+	// and Usable set to false. The failures are returned as a *TraceReport along with the contours.
+	SkipFailedContours bool
+}
+
 // FindContours takes a SuzukiImage and determines the Contours that are present.
 // It returns the single parent contour which in turn has all other contours as children or further
 // generations.
 func FindContours(img *common.SuzukiImage) (*Contour, error) {
+	return FindContoursCtx(context.Background(), img, FindOptions{})
+}
+
+// FindContoursCtx is FindContours with cancellation and progress reporting.
+// The context is checked at the start of each row; if it is cancelled or its deadline passes the
+// context error is returned. The image will have been partially modified in that case.
+// A contour that can not be traced results in a *TraceError, unless opts.SkipFailedContours is set.
+func FindContoursCtx(ctx context.Context, img *common.SuzukiImage, opts FindOptions) (*Contour, error) {
+	logger := opts.Logger
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+
+	if opts.ChainCode && opts.Approximation != ApproxNone {
+		return nil, ErrApproxChainCode
+	}
+
+	// ApproxSimple is applied while tracing, the other approximations once each border is complete.
+	compress := opts.Approximation == ApproxSimple
+
 	nbd := 1
 	lnbd := 1
 
 	contours := make(map[int]*Contour)
+	failures := []*TraceError{}
 	done := []bool{false, false, false, false, false, false, false, false}
 
 	contour := NewContour(1)
@@ -31,11 +87,22 @@ func FindContours(img *common.SuzukiImage) (*Contour, error) {
 	width := img.Width
 
 	for i := 0; i < height; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		lnbd = 1
+
+		// inside a top level component, only tracked for RetrExternal.
+		inside := false
 		for j := 0; j < width; j++ {
 			fji := img.GetXY(j, i)
 			isOuter := fji == 1 && (j == 0 || img.GetXY(j-1, i) == 0)
 			isHole := fji >= 1 && (j == width-1 || img.GetXY(j+1, i) == 0)
+			if opts.Mode == RetrExternal {
+				isOuter = isOuter && !inside
+				isHole = false
+			}
 			if isOuter || isHole {
 
 				var contourPrime *Contour
@@ -68,10 +135,17 @@ func FindContours(img *common.SuzukiImage) (*Contour, error) {
 				}
 
 				p0 := image.Point{j, i}
-				border, collectionIndices, err := createBorder(img, p0, from, nbd, done)
-				if err != nil {
-					log.Errorf("unable to create border: %s", err.Error())
-					return nil, err
+				border, collectionIndices, traceErr := createBorder(img, p0, from, nbd, done, opts.Connectivity, compress && !opts.PixelEdges, logger)
+				if traceErr != nil {
+					logger.Error("unable to create border", "x", p0.X, "y", p0.Y, "nbd", nbd, "err", traceErr)
+					if img.HasPadding() {
+						traceErr.offset(image.Point{-1, -1})
+					}
+					if !opts.SkipFailedContours {
+						return nil, traceErr
+					}
+					contour.Usable = false
+					failures = append(failures, traceErr)
 				}
 
 				if len(border) == 0 {
@@ -85,11 +159,36 @@ func FindContours(img *common.SuzukiImage) (*Contour, error) {
 					contour.Parent = contours[parentId]
 				}
 				contour.ParentId = parentId
+				if opts.PixelEdges {
+					border = crackBorder(img, p0, isOuter, opts.Connectivity, compress)
+				}
+				if !compress {
+					border = approximateBorder(border, opts.Approximation)
+				}
 				contour.Points = border
+				if opts.ChainCode {
+					cc, err := NewChainCode(border)
+					if err != nil {
+						return nil, err
+					}
+					contour.ChainCode = &cc
+					contour.Points = nil
+				}
 				contour.Id = nbd
 				contours[nbd] = contour
 				addCollisionFlag(contour, parentId, contours, collectionIndices)
 			}
+
+			// only the outer borders of top level components are traced, which are marked negative where the
+			// pixel to the right is outside the component. So skip everything until then.
+			if opts.Mode == RetrExternal {
+				if fji != 0 {
+					inside = true
+				}
+				if img.GetXY(j, i) < 0 {
+					inside = false
+				}
+			}
 			if fji != 0 && fji != 1 {
 				lnbd = fji
 				if lnbd < 0 {
@@ -97,25 +196,44 @@ func FindContours(img *common.SuzukiImage) (*Contour, error) {
 				}
 			}
 		}
+
+		if opts.Progress != nil {
+			opts.Progress(common.Progress{RowsScanned: i + 1, TotalRows: height, ContoursFound: nbd - 1})
+		}
 	}
 
+	logger.Debug("found contours", "count", nbd-1, "width", width, "height", height)
 	finalContour := contours[1]
+	arrangeContours(finalContour, opts.Mode)
 
 	// image was padded... so now shift every co-ord by -1,-1
 	if img.HasPadding() {
-		shiftContour(finalContour)
+		offsetContour(finalContour, image.Point{-1, -1})
+	}
+
+	if len(failures) > 0 {
+		return finalContour, &TraceReport{Errors: failures}
 	}
 	return finalContour, nil
 }
 
-// clockwise determines direction if we have 'dir' and turn clockwise
-func clockwise(dir int) int {
-	return (dir + 1) % 8
+// clockwise determines direction if we have 'dir' and turn clockwise by step (see directionStep)
+func clockwise(dir int, step int) int {
+	return (dir + step) % 8
 }
 
-// counterClockwise determines direction if we have 'dir' and turn counterclockwise
-func counterClockwise(dir int) int {
-	return (dir + 7) % 8
+// counterClockwise determines direction if we have 'dir' and turn counterclockwise by step (see directionStep)
+func counterClockwise(dir int, step int) int {
+	return (dir + 8 - step) % 8
+}
+
+// directionStep is the number of dirDelta entries to turn between neighbours. With 4 connectivity only
+// the N, E, S and W neighbours are used.
+func directionStep(connectivity common.Connectivity) int {
+	if connectivity == common.Connectivity4 {
+		return 2
+	}
+	return 1
 }
 
 // move moves the current point (pixel) in the direction 'dir'
@@ -141,13 +259,16 @@ func calcDir(from image.Point, to image.Point) (int, error) {
 		}
 	}
 
-	return 0, errors.New("unable to determine direction")
+	return 0, ErrNoDirection
 }
 
 // createBorder returns the slice of Points making up the border/contour
 // Also returns list of nbd's that are colliding with this. Can use to help create
 // tree with collision info later.
-func createBorder(img *common.SuzukiImage, p0 image.Point, p2 image.Point, nbd int, done []bool) ([]image.Point, map[int]bool, error) {
+// If the border can not be followed a *TraceError is returned along with the points traced so far.
+// If compress is set only the end points of straight runs are kept (ApproxSimple).
+func createBorder(img *common.SuzukiImage, p0 image.Point, p2 image.Point, nbd int, done []bool, connectivity common.Connectivity, compress bool, logger *slog.Logger) ([]image.Point, map[int]bool, *TraceError) {
+	step := directionStep(connectivity)
 
 	// track which borders have conflicts
 	collisionIndicies := make(map[int]bool)
@@ -155,11 +276,11 @@ func createBorder(img *common.SuzukiImage, p0 image.Point, p2 image.Point, nbd i
 	border := []image.Point{}
 	dir, err := calcDir(p0, p2)
 	if err != nil {
-		log.Errorf("unable to determine direction: %s", err.Error())
-		return nil, nil, err
+		logger.Error("unable to determine direction", "from", p0, "to", p2, "err", err)
+		return border, collisionIndicies, &TraceError{Start: p0, Point: p0, Nbd: nbd, Dir: -1, Err: err}
 	}
 
-	moved := clockwise(dir)
+	moved := clockwise(dir, step)
 	p1 := image.Point{0, 0}
 	for moved != dir {
 		newP := move(p0, img, moved)
@@ -167,7 +288,7 @@ func createBorder(img *common.SuzukiImage, p0 image.Point, p2 image.Point, nbd i
 			p1 = newP
 			break
 		}
-		moved = clockwise(moved)
+		moved = clockwise(moved, step)
 	}
 
 	if p1.X == 0 && p1.Y == 0 {
@@ -176,22 +297,30 @@ func createBorder(img *common.SuzukiImage, p0 image.Point, p2 image.Point, nbd i
 	p2 = p1
 	p3 := p0
 
-	for {
+	// the trace is deterministic, so if a step repeats before the border closes it never will (eg. p0 is on the
+	// edge of an unpadded image, which move can't return to). Detected by comparing each step to the one saved
+	// at the last power of 2 steps (Brent's cycle detection).
+	var saved [2]image.Point
+	for steps := 0; ; steps++ {
 		dir, err = calcDir(p3, p2)
 		if err != nil {
-			log.Errorf("unable to determine direction: %s", err.Error())
-			return nil, nil, err
+			logger.Error("unable to determine direction", "from", p3, "to", p2, "err", err)
+			return border, collisionIndicies, &TraceError{Start: p0, Point: p3, Nbd: nbd, Dir: -1, Err: err}
 		}
-		moved = counterClockwise(dir)
+		moved = counterClockwise(dir, step)
 		p4 := image.Point{0, 0}
 		done = []bool{false, false, false, false, false, false, false, false}
-		for {
+		for tries := 0; ; tries++ {
+			if tries == len(dirDelta)/step {
+				logger.Error("no neighbour to continue border", "point", p3, "err", ErrNoNeighbour)
+				return border, collisionIndicies, &TraceError{Start: p0, Point: p3, Nbd: nbd, Dir: moved, Err: ErrNoNeighbour}
+			}
 			p4 = move(p3, img, moved)
 			if p4.Y != 0 {
 				break
 			}
 			done[moved] = true
-			moved = counterClockwise(moved)
+			moved = counterClockwise(moved, step)
 		}
 
 		// detect if colliding with something else (ie not 0 nor 1)
@@ -209,7 +338,7 @@ func createBorder(img *common.SuzukiImage, p0 image.Point, p2 image.Point, nbd i
 			collisionIndicies[absNbd] = true
 		}
 
-		border = append(border, p3)
+		border = appendPoint(border, p3, compress)
 		if p3.Y == img.Height-1 || done[2] {
 			img.Set(p3, -1*nbd)
 		} else if img.Get(p3) == 1 {
@@ -220,11 +349,20 @@ func createBorder(img *common.SuzukiImage, p0 image.Point, p2 image.Point, nbd i
 			break
 		}
 
+		state := [2]image.Point{p3, p4}
+		if state == saved {
+			logger.Error("border does not close", "start", p0, "point", p3, "err", ErrNotClosed)
+			return border, collisionIndicies, &TraceError{Start: p0, Point: p3, Nbd: nbd, Dir: moved, Err: ErrNotClosed}
+		}
+		if steps&(steps-1) == 0 {
+			saved = state
+		}
+
 		p2 = p3
 		p3 = p4
 	}
 
-	return border, collisionIndicies, nil
+	return closeBorder(border, compress), collisionIndicies, nil
 }
 
 // addCollisionFlag mark contours with collisions with other contours.
@@ -245,13 +383,16 @@ func addCollisionFlag(contour *Contour, parentId int, contours map[int]*Contour,
 	}
 }
 
-func shiftContour(contour *Contour) {
-	for i, _ := range contour.Points {
-		contour.Points[i].X = contour.Points[i].X - 1
-		contour.Points[i].Y = contour.Points[i].Y - 1
+// offsetContour moves every point of the contour, and all children, by offset.
+func offsetContour(contour *Contour, offset image.Point) {
+	for i := range contour.Points {
+		contour.Points[i] = contour.Points[i].Add(offset)
+	}
+	if contour.ChainCode != nil {
+		contour.ChainCode.Start = contour.ChainCode.Start.Add(offset)
 	}
 
 	for _, child := range contour.Children {
-		shiftContour(child)
+		offsetContour(child, offset)
 	}
 }
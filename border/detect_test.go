@@ -1,9 +1,14 @@
 package border
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"image"
+	"log/slog"
 	"slices"
+	"strings"
 	"testing"
 
 	"github.com/kpfaulkner/borders/common"
@@ -119,3 +124,163 @@ func createFullSuzukiImage(width int, height int) *common.SuzukiImage {
 	}
 	return si
 }
+
+// TestFindContoursCompactStorage checks compact storage generates identical contours to dense storage.
+func TestFindContoursCompactStorage(t *testing.T) {
+	for _, filename := range []string{`../testimages/unittest1.png`, `../testimages/image1.png`, `../testimages/florida.png`} {
+		t.Run(filename, func(t *testing.T) {
+			dense, err := LoadImageWithOptions(filename, LoadOptions{Erode: 1, Dilate: 1})
+			if err != nil {
+				t.Fatalf("Unable to load test image: %s", err.Error())
+			}
+
+			compact, err := LoadImageWithOptions(filename, LoadOptions{Erode: 1, Dilate: 1, Storage: common.CompactStorage})
+			if err != nil {
+				t.Fatalf("Unable to load test image: %s", err.Error())
+			}
+
+			if compact.Storage() != common.CompactStorage || !compact.Equals(dense) {
+				t.Fatalf("expected compact image equal to dense image")
+			}
+
+			denseCont, err := FindContours(dense)
+			if err != nil {
+				t.Fatalf("Unable to find contours: %s", err.Error())
+			}
+
+			compactCont, err := FindContours(compact)
+			if err != nil {
+				t.Fatalf("Unable to find contours: %s", err.Error())
+			}
+
+			if !slices.Equal(denseCont.GetAllPoints(), compactCont.GetAllPoints()) {
+				t.Errorf("expected compact contour points to match dense contour points")
+			}
+		})
+	}
+}
+
+func BenchmarkFindContoursDense(b *testing.B) {
+	benchmarkFindContours(b, common.DenseStorage)
+}
+
+func BenchmarkFindContoursCompact(b *testing.B) {
+	benchmarkFindContours(b, common.CompactStorage)
+}
+
+// benchmarkFindContours loads and finds the contours of a large test image.
+func benchmarkFindContours(b *testing.B, storage common.Storage) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		img, err := LoadImageWithOptions(`../testimages/florida.png`, LoadOptions{Storage: storage})
+		if err != nil {
+			b.Fatalf("Unable to load test image: %s", err.Error())
+		}
+
+		_, err = FindContours(img)
+		if err != nil {
+			b.Fatalf("Unable to find contours: %s", err.Error())
+		}
+	}
+}
+
+// TestFindContoursCtx tests cancellation and progress reporting.
+func TestFindContoursCtx(t *testing.T) {
+	testCases := []struct {
+		name        string
+		cancelAfter int // cancel the context once this many rows have been scanned. 0 means never.
+		expectErr   error
+	}{
+		{
+			name: "success",
+		},
+		{
+			name:        "error when cancelled part way through",
+			cancelAfter: 10,
+			expectErr:   context.Canceled,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			img, err := LoadImage(`../testimages/unittest1.png`, 1, 1)
+			if err != nil {
+				t.Fatalf("Unable to load test image: %s", err.Error())
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var last common.Progress
+			calls := 0
+			opts := FindOptions{
+				Progress: func(p common.Progress) {
+					calls++
+					last = p
+					if p.RowsScanned == tc.cancelAfter {
+						cancel()
+					}
+				},
+			}
+
+			contour, err := FindContoursCtx(ctx, img, opts)
+			if tc.expectErr != nil {
+				if !errors.Is(err, tc.expectErr) {
+					t.Fatalf("expected error %v, got %v", tc.expectErr, err)
+				}
+				if calls != tc.cancelAfter {
+					t.Errorf("expected scanning to stop after %d rows, got %d", tc.cancelAfter, calls)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if calls != img.Height || last.RowsScanned != img.Height || last.TotalRows != img.Height {
+				t.Errorf("expected progress for all %d rows, got %d calls and %+v", img.Height, calls, last)
+			}
+
+			ids := contourIds(contour)
+			if last.ContoursFound != len(ids)-1 {
+				t.Errorf("expected %d contours found, got %d", len(ids)-1, last.ContoursFound)
+			}
+		})
+	}
+}
+
+// TestFindContoursLogger tests logging only goes to the supplied logger.
+func TestFindContoursLogger(t *testing.T) {
+	img, err := LoadImage(`../testimages/unittest1.png`, 1, 1)
+	if err != nil {
+		t.Fatalf("Unable to load test image: %s", err.Error())
+	}
+
+	buf := bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	contour, err := FindContoursCtx(context.Background(), img, FindOptions{Logger: logger})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "msg=\"found contours\" count=4") {
+		t.Errorf("expected contour count to be logged, got %q", buf.String())
+	}
+
+	buf.Reset()
+	ContourStats(contour, 0, logger)
+	if strings.Count(buf.String(), "msg=\"contour stats\"") != 4 {
+		t.Errorf("expected stats for 4 contours, got %q", buf.String())
+	}
+
+	buf.Reset()
+	ContourStatsWithCollisions(contour, 0, logger)
+	if strings.Count(buf.String(), "msg=\"contour stats\"") != 2 {
+		t.Errorf("expected stats for 2 colliding contours, got %q", buf.String())
+	}
+
+	// nil logger is silent.
+	ContourStats(contour, 0, nil)
+	ContourStatsWithCollisions(contour, 0, nil)
+}
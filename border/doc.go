@@ -5,12 +5,15 @@
 // This is based off original work based off the paper as well as inspired by other papers and implementations.
 //
 // The primary function supplied in this package is the FindContours function. This takes a SuzukiImage and
-// returns a Contour instance.
+// returns a Contour instance. FindContoursCtx does the same but can be cancelled and reports progress
+// as each row of the image is scanned. FindContoursMarchingSquares finds sub-pixel contours of a grayscale
+// or probability Field, in the same tree shape.
 //
 // A Contour contains all the points of a border. Note: the border is not just the outer border but can
 // contain "holes" and sub-borders.
 //
 // This package also converts a PNG image (will extend to other formats in the future) and generates
 // a SuzukiImage instance. A SuzukiImage effectively a wrapper to a byte array with helper functions used
-// to make border dection easier.
+// to make border dection easier. LoadFromReader and LoadFromImage perform the same conversion for
+// images that are not stored in a file.
 package border
@@ -0,0 +1,68 @@
+package border
+
+import (
+	"errors"
+	"fmt"
+	"image"
+)
+
+var (
+	ErrNoDirection = errors.New("unable to determine direction")
+	ErrNoNeighbour = errors.New("no neighbouring pixel to continue border")
+	ErrNotClosed   = errors.New("border does not return to its starting pixel")
+)
+
+// TraceError records where border following failed. Use errors.As to retrieve it from the error returned
+// by FindContours, and errors.Is with ErrNoDirection/ErrNoNeighbour/ErrNotClosed to determine the cause.
+// Points are in the same co-ords as the returned contours (ie. padding removed).
+type TraceError struct {
+
+	// Start is the first pixel of the contour being traced.
+	Start image.Point
+
+	// Point is the pixel being traced when the failure occurred.
+	Point image.Point
+
+	// Nbd is the Id the contour would have been assigned.
+	Nbd int
+
+	// Dir is the index (0-7, clockwise from north) of the direction being examined.
+	Dir int
+
+	Err error
+}
+
+func (e *TraceError) Error() string {
+	return fmt.Sprintf("unable to trace contour %d starting at %v: at %v direction %d: %v", e.Nbd, e.Start, e.Point, e.Dir, e.Err)
+}
+
+func (e *TraceError) Unwrap() error {
+	return e.Err
+}
+
+// TraceReport is returned (along with the partial contour tree) by FindContoursCtx when
+// FindOptions.SkipFailedContours is set and one or more contours could not be traced.
+type TraceReport struct {
+	Errors []*TraceError
+}
+
+func (r *TraceReport) Error() string {
+	if len(r.Errors) == 1 {
+		return r.Errors[0].Error()
+	}
+	return fmt.Sprintf("%d contours failed to trace, first: %v", len(r.Errors), r.Errors[0])
+}
+
+func (r *TraceReport) Unwrap() []error {
+	errs := make([]error, len(r.Errors))
+	for i, e := range r.Errors {
+		errs[i] = e
+	}
+	return errs
+}
+
+// offset moves the points of the error by offset.
+func (e *TraceError) offset(offset image.Point) {
+	e.Start = e.Start.Add(offset)
+	e.Point = e.Point.Add(offset)
+}
@@ -0,0 +1,187 @@
+package border
+
+import (
+	"context"
+	"errors"
+	"image"
+	"log/slog"
+	"testing"
+
+	"github.com/kpfaulkner/borders/common"
+)
+
+// TestFindContoursTraceErrors tests failures are returned as typed errors, and optionally skipped.
+func TestFindContoursTraceErrors(t *testing.T) {
+	testCases := []struct {
+		name      string
+		width     int
+		height    int
+		imageData []int
+		skip      bool
+
+		expectedErr      error
+		expectedFailures []TraceError
+
+		// Usable flag for each contour Id (excluding the root).
+		expectedUsable map[int]bool
+	}{
+		{
+			name:   "success",
+			width:  4,
+			height: 4,
+			imageData: []int{
+				0, 0, 0, 0,
+				0, 1, 1, 0,
+				0, 1, 1, 0,
+				0, 0, 0, 0},
+			expectedUsable: map[int]bool{2: true},
+		},
+		{
+			name:   "error with foreground in corner of unpadded image",
+			width:  4,
+			height: 4,
+			imageData: []int{
+				1, 1, 0, 0,
+				1, 1, 0, 0,
+				0, 0, 0, 0,
+				0, 0, 0, 0},
+			expectedErr:      ErrNoNeighbour,
+			expectedFailures: []TraceError{{Start: image.Point{0, 0}, Point: image.Point{1, 1}, Nbd: 2}},
+		},
+		{
+			name:   "success skipping failed contour",
+			width:  6,
+			height: 6,
+			imageData: []int{
+				1, 1, 0, 0, 0, 0,
+				1, 1, 0, 0, 0, 0,
+				0, 0, 0, 0, 0, 0,
+				0, 0, 0, 1, 1, 0,
+				0, 0, 0, 1, 1, 0,
+				0, 0, 0, 0, 0, 0},
+			skip:        true,
+			expectedErr: ErrNoNeighbour,
+
+			// the untraced pixels of the first failure start further contours, which also fail. The holes (3 and 5)
+			// have no parent so are not in the tree.
+			expectedFailures: []TraceError{
+				{Start: image.Point{0, 0}, Point: image.Point{1, 1}, Nbd: 2},
+				{Start: image.Point{1, 0}, Point: image.Point{1, 1}, Nbd: 3},
+				{Start: image.Point{0, 1}, Point: image.Point{1, 1}, Nbd: 4},
+			},
+			expectedUsable: map[int]bool{2: false, 4: false, 6: true},
+		},
+		{
+			name:   "error with foreground on top edge of unpadded image",
+			width:  4,
+			height: 4,
+			imageData: []int{
+				0, 1, 1, 0,
+				0, 1, 1, 0,
+				0, 0, 0, 0,
+				0, 0, 0, 0},
+			skip:        true,
+			expectedErr: ErrNotClosed,
+
+			// the border can't return to the top row, so would otherwise loop forever.
+			expectedFailures: []TraceError{
+				{Start: image.Point{1, 0}, Point: image.Point{2, 1}, Nbd: 2},
+				{Start: image.Point{2, 0}, Point: image.Point{2, 1}, Nbd: 3},
+			},
+			expectedUsable: map[int]bool{2: false},
+		},
+		{
+			name:   "error with foreground on left edge of unpadded image",
+			width:  4,
+			height: 4,
+			imageData: []int{
+				1, 1, 0, 0,
+				1, 1, 0, 0,
+				1, 1, 0, 0,
+				0, 0, 0, 0},
+			expectedErr:      ErrNotClosed,
+			expectedFailures: []TraceError{{Start: image.Point{0, 0}, Point: image.Point{1, 2}, Nbd: 2}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			img := common.NewSuzukiImageFromData(tc.width, tc.height, false, tc.imageData)
+			contour, err := FindContoursCtx(context.Background(), img, FindOptions{SkipFailedContours: tc.skip})
+
+			if tc.expectedErr == nil {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+			} else {
+				if !errors.Is(err, tc.expectedErr) {
+					t.Fatalf("expected error %v, got %v", tc.expectedErr, err)
+				}
+
+				var traceErrs []*TraceError
+				var report *TraceReport
+				var traceErr *TraceError
+				if errors.As(err, &report) {
+					traceErrs = report.Errors
+				} else if errors.As(err, &traceErr) {
+					traceErrs = []*TraceError{traceErr}
+				}
+
+				if len(traceErrs) != len(tc.expectedFailures) {
+					t.Fatalf("expected %d failures, got %d", len(tc.expectedFailures), len(traceErrs))
+				}
+				for i, expected := range tc.expectedFailures {
+					got := traceErrs[i]
+					if got.Start != expected.Start || got.Point != expected.Point || got.Nbd != expected.Nbd {
+						t.Errorf("expected failure %+v, got %+v", expected, *got)
+					}
+				}
+			}
+
+			if !tc.skip && tc.expectedErr != nil {
+				if contour != nil {
+					t.Errorf("expected no contour when not skipping failures")
+				}
+				return
+			}
+
+			usable := map[int]bool{}
+			var collect func(c *Contour)
+			collect = func(c *Contour) {
+				for _, ch := range c.Children {
+					usable[ch.Id] = ch.Usable
+					collect(ch)
+				}
+			}
+			collect(contour)
+
+			if len(usable) != len(tc.expectedUsable) {
+				t.Fatalf("expected %d contours, got %d", len(tc.expectedUsable), len(usable))
+			}
+			for id, expected := range tc.expectedUsable {
+				if usable[id] != expected {
+					t.Errorf("expected contour %d usable %v, got %v", id, expected, usable[id])
+				}
+			}
+		})
+	}
+}
+
+// TestCreateBorderNoDirection tests an invalid starting direction is reported.
+func TestCreateBorderNoDirection(t *testing.T) {
+	img := common.NewSuzukiImageFromData(4, 4, false, []int{
+		0, 0, 0, 0,
+		0, 1, 1, 0,
+		0, 1, 1, 0,
+		0, 0, 0, 0})
+
+	logger := slog.New(slog.DiscardHandler)
+	_, _, err := createBorder(img, image.Point{1, 1}, image.Point{3, 1}, 2, make([]bool, 8), common.Connectivity8, false, logger)
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+
+	if !errors.Is(err, ErrNoDirection) || err.Start != (image.Point{1, 1}) || err.Nbd != 2 {
+		t.Errorf("expected ErrNoDirection at 1,1 for contour 2, got %v", err)
+	}
+}
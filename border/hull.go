@@ -0,0 +1,145 @@
+package border
+
+import (
+	"image"
+	"math"
+	"sort"
+)
+
+// ConvexityDefect is a concave region of a contour between two neighbouring points of its convex hull.
+type ConvexityDefect struct {
+
+	// Start and End are the hull points either side of the defect.
+	Start image.Point
+	End   image.Point
+
+	// Farthest is the contour point between Start and End that is farthest from the hull.
+	Farthest image.Point
+
+	// Indices of Start, End and Farthest in the contour Points.
+	StartIndex    int
+	EndIndex      int
+	FarthestIndex int
+
+	// Depth is the distance from Farthest to the line between Start and End.
+	Depth float64
+}
+
+// ConvexHull returns the points of the convex hull of the contour, using Andrew's monotone chain algorithm.
+// The points are in the same direction as Outer contours (anti-clockwise when displayed) starting from the
+// top-most of the left-most points. Points along the edges of the hull are not included.
+func ConvexHull(c *Contour) []image.Point {
+	indices := convexHullIndices(c.Points)
+	hull := make([]image.Point, len(indices))
+	for i, idx := range indices {
+		hull[i] = c.Points[idx]
+	}
+	return hull
+}
+
+// ConvexityDefects returns the concave regions of the contour, ie. every section of the contour between
+// two neighbouring hull points that deviates from the hull, in the order they appear in the contour.
+func ConvexityDefects(c *Contour) []ConvexityDefect {
+	indices := convexHullIndices(c.Points)
+	if len(indices) < 3 {
+		return []ConvexityDefect{}
+	}
+
+	// hull points appear in the same cyclic order in the contour, so walk them in contour order.
+	sort.Ints(indices)
+
+	n := len(c.Points)
+	defects := []ConvexityDefect{}
+	for i, startIdx := range indices {
+		endIdx := indices[(i+1)%len(indices)]
+		start := c.Points[startIdx]
+		end := c.Points[endIdx]
+
+		defect := ConvexityDefect{Start: start, End: end, StartIndex: startIdx, EndIndex: endIdx}
+		for idx := (startIdx + 1) % n; idx != endIdx; idx = (idx + 1) % n {
+			depth := distanceToLine(c.Points[idx], start, end)
+			if depth > defect.Depth {
+				defect.Depth = depth
+				defect.Farthest = c.Points[idx]
+				defect.FarthestIndex = idx
+			}
+		}
+
+		if defect.Depth > 0 {
+			defects = append(defects, defect)
+		}
+	}
+	return defects
+}
+
+// Solidity returns the ratio of the contour area to the area of its convex hull. It is 1 for convex
+// contours and decreases as the contour becomes more concave. Contours with no area return 0.
+func Solidity(c *Contour) float64 {
+	hull := Contour{Points: ConvexHull(c)}
+	hullArea := hull.Area()
+	if hullArea == 0 {
+		return 0
+	}
+	return c.Area() / hullArea
+}
+
+// convexHullIndices returns the indices (into points) of the convex hull. Where a point appears multiple
+// times in the contour the first occurrence is used.
+func convexHullIndices(points []image.Point) []int {
+	seen := make(map[image.Point]bool)
+	sorted := []int{}
+	for i, p := range points {
+		if !seen[p] {
+			seen[p] = true
+			sorted = append(sorted, i)
+		}
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		pi, pj := points[sorted[i]], points[sorted[j]]
+		if pi.X != pj.X {
+			return pi.X < pj.X
+		}
+		return pi.Y < pj.Y
+	})
+
+	if len(sorted) < 3 {
+		return sorted
+	}
+
+	// build the hull along the bottom (when displayed) then back along the top, only keeping anti-clockwise
+	// turns (cross < 0 as y points down).
+	hull := make([]int, 0, 2*len(sorted))
+	for _, idx := range sorted {
+		for len(hull) >= 2 && cross(points[hull[len(hull)-2]], points[hull[len(hull)-1]], points[idx]) >= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, idx)
+	}
+
+	lower := len(hull) + 1
+	for i := len(sorted) - 2; i >= 0; i-- {
+		idx := sorted[i]
+		for len(hull) >= lower && cross(points[hull[len(hull)-2]], points[hull[len(hull)-1]], points[idx]) >= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, idx)
+	}
+
+	// last point is the same as the first.
+	return hull[:len(hull)-1]
+}
+
+// cross returns the z component of the cross product of o->a and o->b.
+func cross(o image.Point, a image.Point, b image.Point) int {
+	return (a.X-o.X)*(b.Y-o.Y) - (a.Y-o.Y)*(b.X-o.X)
+}
+
+// distanceToLine returns the distance from p to the line through a and b.
+func distanceToLine(p image.Point, a image.Point, b image.Point) float64 {
+	length := math.Hypot(float64(b.X-a.X), float64(b.Y-a.Y))
+	if length == 0 {
+		return math.Hypot(float64(p.X-a.X), float64(p.Y-a.Y))
+	}
+	return math.Abs(float64(cross(a, b, p))) / length
+}
@@ -0,0 +1,134 @@
+package border
+
+import (
+	"image"
+	"math"
+	"slices"
+	"testing"
+)
+
+// TestConvexHull tests the hull, defects and solidity of simple shapes.
+func TestConvexHull(t *testing.T) {
+	testCases := []struct {
+		name   string
+		points []image.Point
+
+		expectedHull     []image.Point
+		expectedDefects  []ConvexityDefect
+		expectedSolidity float64
+	}{
+		{
+			name:             "success square with points along edges",
+			points:           []image.Point{{0, 0}, {0, 1}, {0, 2}, {1, 2}, {2, 2}, {2, 1}, {2, 0}, {1, 0}},
+			expectedHull:     []image.Point{{0, 0}, {0, 2}, {2, 2}, {2, 0}},
+			expectedDefects:  []ConvexityDefect{},
+			expectedSolidity: 1,
+		},
+		{
+			name:             "success clockwise triangle",
+			points:           []image.Point{{0, 0}, {6, 0}, {0, 3}},
+			expectedHull:     []image.Point{{0, 0}, {0, 3}, {6, 0}},
+			expectedDefects:  []ConvexityDefect{},
+			expectedSolidity: 1,
+		},
+		{
+			name:         "success U shape",
+			points:       []image.Point{{0, 0}, {0, 4}, {4, 4}, {4, 0}, {3, 0}, {3, 3}, {1, 3}, {1, 0}},
+			expectedHull: []image.Point{{0, 0}, {0, 4}, {4, 4}, {4, 0}},
+			expectedDefects: []ConvexityDefect{
+				{Start: image.Point{4, 0}, End: image.Point{0, 0}, Farthest: image.Point{3, 3}, StartIndex: 3, EndIndex: 0, FarthestIndex: 5, Depth: 3},
+			},
+			expectedSolidity: 10.0 / 16,
+		},
+		{
+			name:             "success with repeated points",
+			points:           []image.Point{{0, 0}, {0, 2}, {1, 1}, {2, 2}, {1, 1}, {2, 0}},
+			expectedHull:     []image.Point{{0, 0}, {0, 2}, {2, 2}, {2, 0}},
+			expectedSolidity: 0.5,
+			expectedDefects: []ConvexityDefect{
+				{Start: image.Point{0, 2}, End: image.Point{2, 2}, Farthest: image.Point{1, 1}, StartIndex: 1, EndIndex: 3, FarthestIndex: 2, Depth: 1},
+				{Start: image.Point{2, 2}, End: image.Point{2, 0}, Farthest: image.Point{1, 1}, StartIndex: 3, EndIndex: 5, FarthestIndex: 4, Depth: 1},
+			},
+		},
+		{
+			name:             "success single point",
+			points:           []image.Point{{3, 3}},
+			expectedHull:     []image.Point{{3, 3}},
+			expectedDefects:  []ConvexityDefect{},
+			expectedSolidity: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := NewContour(2)
+			c.Points = tc.points
+
+			hull := ConvexHull(c)
+			if !slices.Equal(hull, tc.expectedHull) {
+				t.Errorf("expected hull %v, got %v", tc.expectedHull, hull)
+			}
+
+			defects := ConvexityDefects(c)
+			if len(defects) != len(tc.expectedDefects) {
+				t.Fatalf("expected defects %+v, got %+v", tc.expectedDefects, defects)
+			}
+			for i, expected := range tc.expectedDefects {
+				got := defects[i]
+				got.Depth = math.Round(got.Depth*1e6) / 1e6
+				if got != expected {
+					t.Errorf("expected defect %+v, got %+v", expected, got)
+				}
+			}
+
+			solidity := Solidity(c)
+			if math.Abs(solidity-tc.expectedSolidity) > measureTolerance {
+				t.Errorf("expected solidity %f, got %f", tc.expectedSolidity, solidity)
+			}
+		})
+	}
+}
+
+// TestConvexHullOfFoundContours tests the hull of every contour in an image contains all its points and has
+// the same direction as the outer contours.
+func TestConvexHullOfFoundContours(t *testing.T) {
+	img, err := LoadImage(`../testimages/unittest1.png`, 0, 0)
+	if err != nil {
+		t.Fatalf("Unable to load test image: %s", err.Error())
+	}
+
+	root, err := FindContours(img)
+	if err != nil {
+		t.Fatalf("Unable to find contours: %s", err.Error())
+	}
+
+	var check func(c *Contour)
+	check = func(c *Contour) {
+		for _, child := range c.Children {
+			check(child)
+		}
+
+		hull := ConvexHull(c)
+		if len(hull) < 3 {
+			return
+		}
+
+		h := Contour{Points: hull}
+		if h.SignedArea() >= 0 {
+			t.Errorf("expected hull of contour %d to be anti-clockwise", c.Id)
+		}
+
+		for _, p := range c.Points {
+			for i, a := range hull {
+				if cross(a, hull[(i+1)%len(hull)], p) > 0 {
+					t.Fatalf("point %v of contour %d is outside the hull %v", p, c.Id, hull)
+				}
+			}
+		}
+
+		if s := Solidity(c); s <= 0 || s > 1 {
+			t.Errorf("expected solidity of contour %d between 0 and 1, got %f", c.Id, s)
+		}
+	}
+	check(root)
+}
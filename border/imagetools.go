@@ -1,17 +1,54 @@
 package border
 
 import (
+	"context"
 	"fmt"
 	"image"
 	"image/color"
 	"image/png"
 	_ "image/png"
+	"io"
 	"os"
 
 	"github.com/kpfaulkner/borders/common"
 	image2 "github.com/kpfaulkner/borders/image"
 )
 
+// LoadOptions controls how an image is converted into a SuzukiImage.
+// The zero value treats any non black pixel as foreground and performs no eroding or dilating.
+type LoadOptions struct {
+
+	// Classifier decides which pixels are foreground. If nil, NewNonBlackClassifier is used.
+	Classifier Classifier
+
+	// Erode forces the eroding of the image before converting to a SuzukiImage.
+	// See https://en.wikipedia.org/wiki/Erosion_(morphology) for explanation
+	Erode int
+
+	// Dilate forces the dilating of the image before converting to a SuzukiImage. Likewise, see
+	// https://en.wikipedia.org/wiki/Dilation_(morphology) for explanation.
+	Dilate int
+
+	// MinObjectArea removes connected areas of foreground with fewer pixels, after eroding and dilating.
+	MinObjectArea int
+
+	// FillHoles fills areas of background not connected to the edge of the image, after removing small objects.
+	FillHoles bool
+
+	// MaxHoleArea restricts FillHoles to holes of at most this many pixels. 0 fills every hole.
+	MaxHoleArea int
+
+	// Connectivity of the foreground used by MinObjectArea and FillHoles. This should match the
+	// FindOptions.Connectivity used to find the contours. Defaults to common.Connectivity8.
+	Connectivity common.Connectivity
+
+	// Storage of the generated SuzukiImage. Use common.CompactStorage for very large images.
+	Storage common.Storage
+
+	// Progress, if set, is called after each row of the erode and dilate passes.
+	Progress common.ProgressFunc
+}
+
 // LoadImage loads a PNG and returns a SuzukiImage. Currently restricted to PNG but will eventually expand
 // to include other formats.
 //
@@ -24,6 +61,16 @@ import (
 // The combination of Erode and Dilate helps remove any "spikes" that may appear in the generated boundary.
 // erode and dilate will usually be 0 (none) or 1 (single pixel spikes)
 func LoadImage(filename string, erode int, dilate int) (*common.SuzukiImage, error) {
+	return LoadImageWithOptions(filename, LoadOptions{Erode: erode, Dilate: dilate})
+}
+
+// LoadImageWithOptions loads an image file and returns a SuzukiImage, using opts to control the conversion.
+func LoadImageWithOptions(filename string, opts LoadOptions) (*common.SuzukiImage, error) {
+	return LoadImageCtx(context.Background(), filename, opts)
+}
+
+// LoadImageCtx is LoadImageWithOptions with cancellation. See LoadFromImageCtx.
+func LoadImageCtx(ctx context.Context, filename string, opts LoadOptions) (*common.SuzukiImage, error) {
 
 	f, err := os.Open(filename)
 	if err != nil {
@@ -31,17 +78,54 @@ func LoadImage(filename string, erode int, dilate int) (*common.SuzukiImage, err
 	}
 	defer f.Close()
 
-	img, _, err := image.Decode(f)
+	return LoadFromReaderCtx(ctx, f, opts)
+}
+
+// LoadFromReader decodes an image from r and returns a SuzukiImage.
+// Any format registered with the image package can be decoded, so callers can supply data
+// received over the network or from object storage without writing a temporary file.
+func LoadFromReader(r io.Reader, opts LoadOptions) (*common.SuzukiImage, error) {
+	return LoadFromReaderCtx(context.Background(), r, opts)
+}
+
+// LoadFromReaderCtx is LoadFromReader with cancellation. See LoadFromImageCtx.
+func LoadFromReaderCtx(ctx context.Context, r io.Reader, opts LoadOptions) (*common.SuzukiImage, error) {
+
+	img, _, err := image.Decode(r)
 	if err != nil {
 		return nil, err
 	}
 
+	return LoadFromImageCtx(ctx, img, opts)
+}
+
+// LoadFromImage converts an already decoded image into a SuzukiImage.
+// The image bounds do not need to start at 0,0 (eg. the result of SubImage), the resulting
+// SuzukiImage always does.
+func LoadFromImage(img image.Image, opts LoadOptions) (*common.SuzukiImage, error) {
+	return LoadFromImageCtx(context.Background(), img, opts)
+}
+
+// LoadFromImageCtx is LoadFromImage with cancellation. The context is checked at the start of each row while
+// converting and during the erode and dilate passes (which report to opts.Progress), and before each of the
+// other preprocessing steps.
+func LoadFromImageCtx(ctx context.Context, img image.Image, opts LoadOptions) (*common.SuzukiImage, error) {
+
+	classifier := opts.Classifier
+	if classifier == nil {
+		classifier = NewNonBlackClassifier()
+	}
+	isForeground := classifier(img)
+	colourAt := pixelColours(img)
+
 	// If any pixels on the edges are populated, then we need to pad this out by 1 pixel on each side.
 	// This will be reversed later.
-	requirePadding := doesImageRequirePadding(img)
+	requirePadding := doesImageRequirePadding(img, isForeground, colourAt)
+
+	bounds := img.Bounds()
 
 	// need border to be black. Pad edges with 1 black pixel
-	si := common.NewSuzukiImage(img.Bounds().Dx(), img.Bounds().Dy(), requirePadding)
+	si := common.NewSuzukiImageWithStorage(bounds.Dx(), bounds.Dy(), requirePadding, opts.Storage)
 
 	paddingOffset := 0
 	if requirePadding {
@@ -49,12 +133,14 @@ func LoadImage(filename string, erode int, dilate int) (*common.SuzukiImage, err
 	}
 
 	// dumb... but convert to own image format for now.
-	for y := 0; y < img.Bounds().Dy(); y++ {
-		for x := 0; x < img.Bounds().Dx(); x++ {
+	for y := 0; y < bounds.Dy(); y++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		for x := 0; x < bounds.Dx(); x++ {
 			cc := 0
-			c := img.At(x, y)
-			r, g, b, _ := c.RGBA()
-			if !(r == 0 && g == 0 && b == 0) {
+			if isForeground(colourAt(bounds.Min.X+x, bounds.Min.Y+y)) {
 				cc = 1
 			}
 			si.SetXY(x+paddingOffset, y+paddingOffset, cc)
@@ -62,15 +148,43 @@ func LoadImage(filename string, erode int, dilate int) (*common.SuzukiImage, err
 
 	}
 
-	if erode != 0 {
-		si, err = image2.Erode(si, erode)
+	return preprocess(ctx, si, opts)
+}
+
+// preprocess applies the erode/dilate, small object removal and hole filling options to a SuzukiImage.
+func preprocess(ctx context.Context, si *common.SuzukiImage, opts LoadOptions) (*common.SuzukiImage, error) {
+	var err error
+	if opts.Erode != 0 {
+		si, err = image2.ErodeCtx(ctx, si, opts.Erode, opts.Progress)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.Dilate != 0 {
+		si, err = image2.DilateCtx(ctx, si, opts.Dilate, opts.Progress)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if opts.MinObjectArea > 0 {
+		si, err = image2.RemoveSmallObjects(si, opts.MinObjectArea, opts.Connectivity)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	if dilate != 0 {
-		si, err = image2.Dilate(si, dilate)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if opts.FillHoles {
+		si, err = image2.FillHoles(si, opts.MaxHoleArea, opts.Connectivity)
 		if err != nil {
 			return nil, err
 		}
@@ -80,38 +194,20 @@ func LoadImage(filename string, erode int, dilate int) (*common.SuzukiImage, err
 }
 
 // check down each edge to see if populated, if so, it will require padding
-func doesImageRequirePadding(img image.Image) bool {
+func doesImageRequirePadding(img image.Image, isForeground ForegroundFunc, colourAt func(x int, y int) color.Color) bool {
 
-	// down left/right edge
-	for y := 0; y < img.Bounds().Dy(); y++ {
-		leftEdgeX := 0
-		c := img.At(leftEdgeX, y)
-		r, g, b, _ := c.RGBA()
-		if !(r == 0 && g == 0 && b == 0) {
-			return true
-		}
+	bounds := img.Bounds()
 
-		rightEdgeX := img.Bounds().Dx() - 1
-		c = img.At(rightEdgeX, y)
-		r, g, b, _ = c.RGBA()
-		if !(r == 0 && g == 0 && b == 0) {
+	// down left/right edge
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		if isForeground(colourAt(bounds.Min.X, y)) || isForeground(colourAt(bounds.Max.X-1, y)) {
 			return true
 		}
 	}
 
 	// across top and bottom
-	for x := 0; x < img.Bounds().Dx(); x++ {
-		topEdgeY := 0
-		c := img.At(x, topEdgeY)
-		r, g, b, _ := c.RGBA()
-		if !(r == 0 && g == 0 && b == 0) {
-			return true
-		}
-
-		bottomEdgeY := img.Bounds().Dy() - 1
-		c = img.At(x, bottomEdgeY)
-		r, g, b, _ = c.RGBA()
-		if !(r == 0 && g == 0 && b == 0) {
+	for x := bounds.Min.X; x < bounds.Max.X; x++ {
+		if isForeground(colourAt(x, bounds.Min.Y)) || isForeground(colourAt(x, bounds.Max.Y-1)) {
 			return true
 		}
 	}
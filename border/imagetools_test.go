@@ -1,7 +1,16 @@
 package border
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"os"
+	"slices"
 	"testing"
+
+	"github.com/kpfaulkner/borders/common"
 )
 
 // TestImageTools tests loading/saving of images.
@@ -34,3 +43,224 @@ func TestSaveContourImage(t *testing.T) {
 		t.Errorf("Unable to save contour image: %s", err.Error())
 	}
 }
+
+// TestLoadFromReader checks loading from a reader matches loading from a file.
+func TestLoadFromReader(t *testing.T) {
+
+	fileImage, err := LoadImage(`../testimages/unittest1.png`, 1, 1)
+	if err != nil {
+		t.Fatalf("Unable to load test image: %s", err.Error())
+	}
+
+	f, err := os.Open(`../testimages/unittest1.png`)
+	if err != nil {
+		t.Fatalf("Unable to open test image: %s", err.Error())
+	}
+	defer f.Close()
+
+	readerImage, err := LoadFromReader(f, LoadOptions{Erode: 1, Dilate: 1})
+	if err != nil {
+		t.Fatalf("Unable to load test image from reader: %s", err.Error())
+	}
+
+	if !fileImage.Equals(readerImage) {
+		t.Errorf("image loaded from reader differs from image loaded from file")
+	}
+
+	_, err = LoadFromReader(bytes.NewReader([]byte("not an image")), LoadOptions{})
+	if err == nil {
+		t.Errorf("expected error decoding invalid data, got nil")
+	}
+}
+
+// TestLoadFromImage checks conversion of an in memory image, including one whose bounds do not start at 0,0.
+func TestLoadFromImage(t *testing.T) {
+	testCases := []struct {
+		name          string
+		img           image.Image
+		expectedWidth int
+		hasPadding    bool
+		expectedData  []int
+	}{
+		{
+			name:          "success without padding",
+			img:           createTestImage(image.Rect(0, 0, 3, 3), []image.Point{{1, 1}}),
+			expectedWidth: 3,
+			hasPadding:    false,
+			expectedData:  []int{0, 0, 0, 0, 1, 0, 0, 0, 0},
+		},
+		{
+			name:          "success with padding",
+			img:           createTestImage(image.Rect(0, 0, 2, 2), []image.Point{{0, 0}}),
+			expectedWidth: 4,
+			hasPadding:    true,
+			expectedData:  []int{0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+		},
+		{
+			name:          "success with sub image",
+			img:           createTestImage(image.Rect(0, 0, 6, 6), []image.Point{{4, 4}}).SubImage(image.Rect(3, 3, 6, 6)),
+			expectedWidth: 3,
+			hasPadding:    false,
+			expectedData:  []int{0, 0, 0, 0, 1, 0, 0, 0, 0},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			si, err := LoadFromImage(tc.img, LoadOptions{})
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if si.Width != tc.expectedWidth {
+				t.Errorf("expected width %d, got %d", tc.expectedWidth, si.Width)
+			}
+
+			if si.HasPadding() != tc.hasPadding {
+				t.Errorf("expected padding %v, got %v", tc.hasPadding, si.HasPadding())
+			}
+
+			if slices.Compare(si.GetAllData(), tc.expectedData) != 0 {
+				t.Errorf("expected data %v, got %v", tc.expectedData, si.GetAllData())
+			}
+		})
+	}
+}
+
+// TestLoadFromImageCleanup checks small objects are removed and holes filled when loading.
+func TestLoadFromImageCleanup(t *testing.T) {
+	ring := []image.Point{{1, 1}, {2, 1}, {3, 1}, {1, 2}, {3, 2}, {1, 3}, {2, 3}, {3, 3}}
+	img := createTestImage(image.Rect(0, 0, 6, 5), append(ring, image.Point{5, 0}))
+
+	testCases := []struct {
+		name         string
+		opts         LoadOptions
+		expectedData []int
+	}{
+		{
+			name: "success remove small objects",
+			opts: LoadOptions{MinObjectArea: 2},
+			expectedData: []int{
+				0, 0, 0, 0, 0, 0, 0, 0,
+				0, 0, 0, 0, 0, 0, 0, 0,
+				0, 0, 1, 1, 1, 0, 0, 0,
+				0, 0, 1, 0, 1, 0, 0, 0,
+				0, 0, 1, 1, 1, 0, 0, 0,
+				0, 0, 0, 0, 0, 0, 0, 0,
+				0, 0, 0, 0, 0, 0, 0, 0,
+			},
+		},
+		{
+			name: "success fill holes",
+			opts: LoadOptions{FillHoles: true},
+			expectedData: []int{
+				0, 0, 0, 0, 0, 0, 0, 0,
+				0, 0, 0, 0, 0, 0, 1, 0,
+				0, 0, 1, 1, 1, 0, 0, 0,
+				0, 0, 1, 1, 1, 0, 0, 0,
+				0, 0, 1, 1, 1, 0, 0, 0,
+				0, 0, 0, 0, 0, 0, 0, 0,
+				0, 0, 0, 0, 0, 0, 0, 0,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			si, err := LoadFromImage(img, tc.opts)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if slices.Compare(si.GetAllData(), tc.expectedData) != 0 {
+				t.Errorf("expected data %v, got %v", tc.expectedData, si.GetAllData())
+			}
+		})
+	}
+}
+
+// TestLoadImageCtx tests the erode and dilate passes report progress and can be cancelled.
+func TestLoadImageCtx(t *testing.T) {
+	expected, err := LoadImage(`../testimages/unittest1.png`, 1, 1)
+	if err != nil {
+		t.Fatalf("Unable to load test image: %s", err.Error())
+	}
+
+	testCases := []struct {
+		name string
+
+		// cancelAfter cancels the context after that many progress reports, -1 cancels before loading.
+		cancelAfter int
+		expectErr   error
+	}{
+		{name: "success", cancelAfter: 0},
+		{name: "error cancelled before loading", cancelAfter: -1, expectErr: context.Canceled},
+		{name: "error cancelled while eroding", cancelAfter: 1, expectErr: context.Canceled},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.cancelAfter < 0 {
+				cancel()
+			}
+
+			reports := []common.Progress{}
+			opts := LoadOptions{Erode: 1, Dilate: 1, Progress: func(p common.Progress) {
+				reports = append(reports, p)
+				if len(reports) == tc.cancelAfter {
+					cancel()
+				}
+			}}
+
+			si, err := LoadImageCtx(ctx, `../testimages/unittest1.png`, opts)
+			if tc.expectErr != nil {
+				if !errors.Is(err, tc.expectErr) {
+					t.Fatalf("expected error %v, got %v", tc.expectErr, err)
+				}
+				if len(reports) != max(tc.cancelAfter, 0) {
+					t.Errorf("expected %d progress reports before stopping, got %d", max(tc.cancelAfter, 0), len(reports))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if !si.Equals(expected) {
+				t.Errorf("expected same image as LoadImage")
+			}
+
+			// a complete pass over the rows for each of the erode and dilate passes.
+			passes := 0
+			for i, p := range reports {
+				if p.RowsScanned == 1 {
+					passes++
+				} else if p.RowsScanned != reports[i-1].RowsScanned+1 || p.TotalRows != reports[i-1].TotalRows {
+					t.Fatalf("expected rows to be reported in order, got %+v after %+v", p, reports[i-1])
+				}
+				if (i == len(reports)-1 || reports[i+1].RowsScanned == 1) && p.RowsScanned != p.TotalRows {
+					t.Fatalf("expected every row of each pass to be reported, got %+v", p)
+				}
+			}
+			if passes != 2 {
+				t.Errorf("expected 2 passes, got %d", passes)
+			}
+		})
+	}
+}
+
+// createTestImage creates a black RGBA image with the given pixels set to white.
+func createTestImage(r image.Rectangle, white []image.Point) *image.RGBA {
+	img := image.NewRGBA(r)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.Set(x, y, color.Black)
+		}
+	}
+	for _, p := range white {
+		img.Set(p.X, p.Y, color.White)
+	}
+	return img
+}
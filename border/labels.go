@@ -0,0 +1,134 @@
+package border
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"sort"
+
+	"github.com/kpfaulkner/borders/common"
+)
+
+// labelInfo tracks the area of the image a label occupies.
+type labelInfo struct {
+	bounds      image.Rectangle
+	touchesEdge bool
+}
+
+// ColourLabel returns the label used by FindLabelledContours for a colour in a non paletted image.
+// This is the 8 bit RGBA values packed into a single int (0xRRGGBBAA).
+func ColourLabel(c color.Color) int {
+	r, g, b, a := c.RGBA()
+	return int(r>>8)<<24 | int(g>>8)<<16 | int(b>>8)<<8 | int(a>>8)
+}
+
+// FindLabelledContours finds the contours for every label (class) in an image in a single call.
+// For a paletted image the label is the palette index of the pixel, for any other image the label is
+// ColourLabel of the pixel colour. Pixels matching the background label are ignored.
+//
+// The result is a map of label to root contour, where each root contour is identical to calling
+// LoadFromImage with a classifier matching only that label, followed by FindContours.
+// The image is only read once, then each label is processed using just the area of the image it occupies.
+//
+// The Erode and Dilate options are applied to each label individually. The Classifier option is ignored
+// as the labels determine what is foreground.
+func FindLabelledContours(img image.Image, background int, opts LoadOptions) (map[int]*Contour, error) {
+
+	labelled, infos := labelImage(img, background)
+
+	// keep processing order deterministic.
+	labels := make([]int, 0, len(infos))
+	for label := range infos {
+		labels = append(labels, label)
+	}
+	sort.Ints(labels)
+
+	width := img.Bounds().Dx()
+	height := img.Bounds().Dy()
+
+	// need enough room around each label so eroding/dilating behaves the same as it would for the whole image.
+	margin := max(opts.Erode, opts.Dilate) + 1
+
+	contours := make(map[int]*Contour)
+	for _, label := range labels {
+		info := infos[label]
+		crop := image.Rect(info.bounds.Min.X-margin, info.bounds.Min.Y-margin, info.bounds.Max.X+margin, info.bounds.Max.Y+margin)
+		crop = crop.Intersect(image.Rect(0, 0, width, height))
+
+		si, err := labelToSuzukiImage(labelled, width, label, crop, info.touchesEdge, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		contour, err := FindContours(si)
+		if err != nil {
+			return nil, err
+		}
+
+		offsetContour(contour, crop.Min)
+		contours[label] = contour
+	}
+
+	return contours, nil
+}
+
+// labelImage reads every pixel of the image once, returning the label for each pixel as well as
+// where each label is located.
+func labelImage(img image.Image, background int) ([]int, map[int]*labelInfo) {
+	bounds := img.Bounds()
+	width := bounds.Dx()
+	height := bounds.Dy()
+
+	paletted, isPaletted := img.(*image.Paletted)
+
+	labelled := make([]int, width*height)
+	infos := make(map[int]*labelInfo)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var label int
+			if isPaletted {
+				label = int(paletted.ColorIndexAt(bounds.Min.X+x, bounds.Min.Y+y))
+			} else {
+				label = ColourLabel(img.At(bounds.Min.X+x, bounds.Min.Y+y))
+			}
+			labelled[y*width+x] = label
+
+			if label == background {
+				continue
+			}
+
+			info, ok := infos[label]
+			if !ok {
+				info = &labelInfo{bounds: image.Rect(x, y, x+1, y+1)}
+				infos[label] = info
+			}
+			info.bounds = info.bounds.Union(image.Rect(x, y, x+1, y+1))
+			if x == 0 || y == 0 || x == width-1 || y == height-1 {
+				info.touchesEdge = true
+			}
+		}
+	}
+
+	return labelled, infos
+}
+
+// labelToSuzukiImage generates a SuzukiImage for the crop area of the labelled image, where pixels
+// matching label are set to 1.
+func labelToSuzukiImage(labelled []int, width int, label int, crop image.Rectangle, requirePadding bool, opts LoadOptions) (*common.SuzukiImage, error) {
+	si := common.NewSuzukiImageWithStorage(crop.Dx(), crop.Dy(), requirePadding, opts.Storage)
+
+	paddingOffset := 0
+	if requirePadding {
+		paddingOffset = 1
+	}
+
+	for y := crop.Min.Y; y < crop.Max.Y; y++ {
+		for x := crop.Min.X; x < crop.Max.X; x++ {
+			if labelled[y*width+x] == label {
+				si.SetXY(x-crop.Min.X+paddingOffset, y-crop.Min.Y+paddingOffset, 1)
+			}
+		}
+	}
+
+	return preprocess(context.Background(), si, opts)
+}
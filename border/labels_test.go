@@ -0,0 +1,130 @@
+package border
+
+import (
+	"image"
+	"image/color"
+	"slices"
+	"testing"
+)
+
+// TestFindLabelledContours checks the contours for each label match those generated when loading each
+// label as a separate binary image.
+func TestFindLabelledContours(t *testing.T) {
+
+	palette := color.Palette{color.Black, color.RGBA{255, 0, 0, 255}, color.RGBA{0, 255, 0, 255}, color.RGBA{0, 0, 255, 255}}
+	img := createLabelledImage(palette)
+
+	testCases := []struct {
+		name           string
+		opts           LoadOptions
+		expectedLabels []int
+	}{
+		{
+			name:           "success",
+			expectedLabels: []int{1, 2, 3},
+		},
+		{
+			name:           "success with erode and dilate",
+			opts:           LoadOptions{Erode: 1, Dilate: 1},
+			expectedLabels: []int{1, 2, 3},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			contours, err := FindLabelledContours(img, 0, tc.opts)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if len(contours) != len(tc.expectedLabels) {
+				t.Fatalf("expected %d labels, got %d", len(tc.expectedLabels), len(contours))
+			}
+
+			for _, label := range tc.expectedLabels {
+				cont, ok := contours[label]
+				if !ok {
+					t.Fatalf("expected contour for label %d", label)
+				}
+
+				opts := tc.opts
+				opts.Classifier = NewPaletteIndexClassifier(uint8(label))
+				si, err := LoadFromImage(img, opts)
+				if err != nil {
+					t.Fatalf("unable to load label %d: %v", label, err)
+				}
+				expected, err := FindContours(si)
+				if err != nil {
+					t.Fatalf("unable to find contours for label %d: %v", label, err)
+				}
+
+				if !slices.Equal(cont.GetAllPoints(), expected.GetAllPoints()) {
+					t.Errorf("label %d: expected points %v, got %v", label, expected.GetAllPoints(), cont.GetAllPoints())
+				}
+				if !slices.Equal(contourIds(cont), contourIds(expected)) {
+					t.Errorf("label %d: expected ids %v, got %v", label, contourIds(expected), contourIds(cont))
+				}
+			}
+		})
+	}
+}
+
+// TestFindLabelledContoursColour checks non paletted images are labelled by colour.
+func TestFindLabelledContoursColour(t *testing.T) {
+
+	red := color.RGBA{255, 0, 0, 255}
+	img := image.NewRGBA(image.Rect(0, 0, 5, 5))
+	for x := 1; x < 4; x++ {
+		for y := 1; y < 4; y++ {
+			img.Set(x, y, red)
+		}
+	}
+
+	contours, err := FindLabelledContours(img, ColourLabel(color.Transparent), LoadOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	cont, ok := contours[ColourLabel(red)]
+	if !ok || len(contours) != 1 {
+		t.Fatalf("expected single contour for red, got %v", contours)
+	}
+
+	expected := []image.Point{{1, 1}, {1, 2}, {1, 3}, {2, 3}, {3, 3}, {3, 2}, {3, 1}, {2, 1}}
+	if !slices.Equal(cont.GetAllPoints(), expected) {
+		t.Errorf("expected points %v, got %v", expected, cont.GetAllPoints())
+	}
+}
+
+// createLabelledImage creates a paletted image with 3 labels, one touching the edge and one containing a hole
+// which in turn contains another label.
+func createLabelledImage(palette color.Palette) *image.Paletted {
+	img := image.NewPaletted(image.Rect(0, 0, 14, 10), palette)
+	rows := []string{
+		"11100000000000",
+		"11100000000000",
+		"11000222222000",
+		"00000200002000",
+		"00000203302000",
+		"00000203302000",
+		"00000200002000",
+		"00000222222000",
+		"00000000000000",
+		"00000000000000",
+	}
+	for y, row := range rows {
+		for x, c := range row {
+			img.SetColorIndex(x, y, uint8(c-'0'))
+		}
+	}
+	return img
+}
+
+// contourIds returns the ids of the contour and all children, depth first.
+func contourIds(c *Contour) []int {
+	ids := []int{c.Id}
+	for _, ch := range c.Children {
+		ids = append(ids, contourIds(ch)...)
+	}
+	return ids
+}
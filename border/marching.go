@@ -0,0 +1,366 @@
+package border
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/kpfaulkner/borders/common"
+)
+
+var (
+	ErrInvalidField = errors.New("field must have a positive width and height, and a value for every cell")
+)
+
+// Field is a grid of values, such as a grayscale image or a probability raster, for
+// FindContoursMarchingSquares. Values are sampled at the centre of each pixel.
+type Field struct {
+	Width  int
+	Height int
+	Values []float64
+}
+
+// NewField creates a Field of the given size with every value 0.
+func NewField(width int, height int) *Field {
+	return &Field{Width: width, Height: height, Values: make([]float64, width*height)}
+}
+
+// NewFieldFromImage creates a Field of the luminance of each pixel, from 0 (black) to 1 (white).
+func NewFieldFromImage(img image.Image) *Field {
+	bounds := img.Bounds()
+	f := NewField(bounds.Dx(), bounds.Dy())
+	for y := 0; y < f.Height; y++ {
+		for x := 0; x < f.Width; x++ {
+			g := color.Gray16Model.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.Gray16)
+			f.Set(x, y, float64(g.Y)/math.MaxUint16)
+		}
+	}
+	return f
+}
+
+// NewFieldFromSuzukiImage creates a Field with 1 for every populated pixel and 0 otherwise.
+// Padding is removed so the co-ords match the contours found by FindContours.
+func NewFieldFromSuzukiImage(img *common.SuzukiImage) *Field {
+	offset := 0
+	if img.HasPadding() {
+		offset = 1
+	}
+
+	f := NewField(img.Width-2*offset, img.Height-2*offset)
+	for y := 0; y < f.Height; y++ {
+		for x := 0; x < f.Width; x++ {
+			if img.GetXY(x+offset, y+offset) != 0 {
+				f.Set(x, y, 1)
+			}
+		}
+	}
+	return f
+}
+
+// Get returns the value at x,y.
+func (f *Field) Get(x int, y int) float64 {
+	return f.Values[y*f.Width+x]
+}
+
+// Set sets the value at x,y.
+func (f *Field) Set(x int, y int, val float64) {
+	f.Values[y*f.Width+x] = val
+}
+
+// ContourF is a contour with sub-pixel points, as found by FindContoursMarchingSquares.
+// It is arranged in the same tree as Contour, with a root (Id 1) holding the outer borders.
+type ContourF struct {
+
+	// Points making up the contour. Outer borders are anti-clockwise when displayed and Holes clockwise.
+	Points []PointF
+
+	Id int
+
+	// Outer or Hole.
+	BorderType int
+
+	// Id of parent
+	ParentId int
+
+	// Parent links to contours parent
+	Parent *ContourF
+
+	// Children links to contours children
+	Children []*ContourF
+}
+
+// NewContourF create new sub-pixel contour
+func NewContourF(id int) *ContourF {
+	return &ContourF{Id: id, BorderType: Hole}
+}
+
+// SignedArea returns the area of the polygon formed by the contour points.
+// It is positive if the points are clockwise when displayed and negative if anti-clockwise.
+func (c *ContourF) SignedArea() float64 {
+	n := len(c.Points)
+	if n < 3 {
+		return 0
+	}
+
+	sum := 0.0
+	for i, p := range c.Points {
+		next := c.Points[(i+1)%n]
+		sum += p.X*next.Y - next.X*p.Y
+	}
+	return sum / 2.0
+}
+
+// marchingGrid is the field padded by a single cell of background, so every contour is closed.
+type marchingGrid struct {
+	field  *Field
+	level  float64
+	width  int
+	height int
+}
+
+// index returns the index of field co-ord x,y in the padded grid.
+func (g *marchingGrid) index(x int, y int) int {
+	return (y+1)*g.width + x + 1
+}
+
+// isForeground checks if the value at x,y is at or above the level. Anything outside the field (or NaN)
+// is background.
+func (g *marchingGrid) isForeground(x int, y int) bool {
+	if x < 0 || y < 0 || x >= g.field.Width || y >= g.field.Height {
+		return false
+	}
+	return g.field.Get(x, y) >= g.level
+}
+
+// edgeKey identifies the edge from x,y to the right (horizontal) or below (vertical).
+func (g *marchingGrid) edgeKey(x int, y int, vertical bool) int {
+	key := g.index(x, y) * 2
+	if vertical {
+		key++
+	}
+	return key
+}
+
+// crossing returns the point where the level crosses the edge, interpolating between the values either side.
+// If either value is outside the field (or not finite) the crossing is half way.
+func (g *marchingGrid) crossing(key int) PointF {
+	idx := key / 2
+	x := idx%g.width - 1
+	y := idx/g.width - 1
+	dx, dy := 1, 0
+	if key%2 == 1 {
+		dx, dy = 0, 1
+	}
+
+	t := 0.5
+	if x >= 0 && y >= 0 && x+dx < g.field.Width && y+dy < g.field.Height {
+		a := g.field.Get(x, y)
+		b := g.field.Get(x+dx, y+dy)
+		if !math.IsInf(a, 0) && !math.IsInf(b, 0) && !math.IsNaN(a) && !math.IsNaN(b) {
+			t = (g.level - a) / (b - a)
+		}
+	}
+	return PointF{X: float64(x) + t*float64(dx), Y: float64(y) + t*float64(dy)}
+}
+
+// FindContoursMarchingSquares finds the contours where the field crosses level, using marching squares.
+// Values at or above level are foreground. Points are interpolated along the edges between pixel centres,
+// so (unlike FindContours) contours of a grayscale or probability raster are smooth rather than a staircase.
+//
+// Diagonally adjacent foreground is treated as connected (matching FindContours) and the result has the same
+// tree shape as FindContours. For a field of 0 and 1 values (eg. NewFieldFromSuzukiImage) with a level of 0.5,
+// the contours match FindContours one for one (Id, type and parent), with points half way between the border
+// pixels and the background.
+func FindContoursMarchingSquares(field *Field, level float64) (*ContourF, error) {
+	if field == nil || field.Width <= 0 || field.Height <= 0 || len(field.Values) != field.Width*field.Height {
+		return nil, ErrInvalidField
+	}
+
+	g := &marchingGrid{field: field, level: level, width: field.Width + 2, height: field.Height + 2}
+
+	// segments keyed by the edge they start from, giving the edge they end on.
+	segments := make([]int, g.width*g.height*2)
+	for i := range segments {
+		segments[i] = -1
+	}
+	starts := []int{}
+
+	for y := -1; y < field.Height; y++ {
+		for x := -1; x < field.Width; x++ {
+			addCellSegments(g, x, y, segments, &starts)
+		}
+	}
+
+	// follow the segments from each unused starting edge (in raster order) to form the rings.
+	rings := [][]int{}
+	for _, start := range starts {
+		if segments[start] < 0 {
+			continue
+		}
+
+		ring := []int{}
+		for key := start; segments[key] >= 0; {
+			ring = append(ring, key)
+			next := segments[key]
+			segments[key] = -1
+			key = next
+		}
+		rings = append(rings, ring)
+	}
+
+	root := NewContourF(1)
+	contours := make([]*ContourF, len(rings))
+	for i, ring := range rings {
+		c := NewContourF(i + 2)
+		c.Points = make([]PointF, len(ring))
+		for j, key := range ring {
+			c.Points[j] = g.crossing(key)
+		}
+		contours[i] = c
+	}
+
+	arrangeRings(g, rings, contours, root)
+	return root, nil
+}
+
+// addCellSegments adds the segments for the cell with top left x,y. The cell edges are walked clockwise and
+// each edge entering the foreground is joined to the previous edge leaving it, so the foreground is on the same
+// side of every segment and diagonally adjacent foreground stays connected.
+func addCellSegments(g *marchingGrid, x int, y int, segments []int, starts *[]int) {
+	corners := [4]image.Point{{x, y}, {x + 1, y}, {x + 1, y + 1}, {x, y + 1}}
+	edges := [4]int{g.edgeKey(x, y, false), g.edgeKey(x+1, y, true), g.edgeKey(x, y+1, false), g.edgeKey(x, y, true)}
+
+	fg := [4]bool{}
+	count := 0
+	for i, c := range corners {
+		fg[i] = g.isForeground(c.X, c.Y)
+		if fg[i] {
+			count++
+		}
+	}
+	if count == 0 || count == 4 {
+		return
+	}
+
+	for i := range corners {
+		// entering the foreground.
+		if fg[i] || !fg[(i+1)%4] {
+			continue
+		}
+
+		// join to the previous edge leaving it, cutting off the background between them.
+		for j := 1; j < 4; j++ {
+			k := (i - j + 4) % 4
+			if fg[k] && !fg[(k+1)%4] {
+				segments[edges[i]] = edges[k]
+				*starts = append(*starts, edges[i])
+				break
+			}
+		}
+	}
+}
+
+// arrangeRings builds the tree of contours. Each ring separates a foreground component (8 connected) from a
+// background component (4 connected), and these components form a tree starting from the background outside
+// the field. Walking that tree determines which rings are outer borders and which are holes.
+func arrangeRings(g *marchingGrid, rings [][]int, contours []*ContourF, root *ContourF) {
+	fgLabels := labelComponents(g, true, dirDelta)
+	bgLabels := labelComponents(g, false, dirDelta4)
+
+	// components either side of each ring, taken from the edge it starts on.
+	type component struct {
+		foreground bool
+		label      int
+	}
+	neighbours := make(map[component][]int)
+	ringFg := make([]int, len(rings))
+	ringBg := make([]int, len(rings))
+	for i, ring := range rings {
+		idx := ring[0] / 2
+		other := idx + 1
+		if ring[0]%2 == 1 {
+			other = idx + g.width
+		}
+		if fgLabels[idx] == 0 {
+			idx, other = other, idx
+		}
+		ringFg[i] = fgLabels[idx]
+		ringBg[i] = bgLabels[other]
+
+		fgc := component{true, ringFg[i]}
+		bgc := component{false, ringBg[i]}
+		neighbours[fgc] = append(neighbours[fgc], i)
+		neighbours[bgc] = append(neighbours[bgc], i)
+	}
+
+	// breadth first from the outside, so each ring is reached from the component containing it.
+	outside := component{false, bgLabels[0]}
+	visited := map[component]*ContourF{outside: root}
+	queue := []component{outside}
+	for len(queue) > 0 {
+		comp := queue[0]
+		queue = queue[1:]
+		parent := visited[comp]
+
+		for _, i := range neighbours[comp] {
+			next := component{true, ringFg[i]}
+			borderType := Outer
+			if comp.foreground {
+				next = component{false, ringBg[i]}
+				borderType = Hole
+			}
+			if _, ok := visited[next]; ok {
+				continue
+			}
+
+			c := contours[i]
+			visited[next] = c
+			queue = append(queue, next)
+			c.BorderType = borderType
+			c.Parent = parent
+			c.ParentId = parent.Id
+		}
+	}
+
+	// children in the order they were found.
+	for _, c := range contours {
+		if c.Parent != nil {
+			c.Parent.Children = append(c.Parent.Children, c)
+		}
+	}
+}
+
+// labelComponents labels the connected foreground (or background) of the padded grid, starting from 1.
+// Pixels not part of a component are 0.
+func labelComponents(g *marchingGrid, foreground bool, neighbours []image.Point) []int {
+	labels := make([]int, g.width*g.height)
+	label := 0
+	for idx := range labels {
+		x, y := idx%g.width-1, idx/g.width-1
+		if labels[idx] != 0 || g.isForeground(x, y) != foreground {
+			continue
+		}
+
+		label++
+		labels[idx] = label
+		queue := []int{idx}
+		for len(queue) > 0 {
+			cur := queue[len(queue)-1]
+			queue = queue[:len(queue)-1]
+			cx, cy := cur%g.width-1, cur/g.width-1
+			for _, d := range neighbours {
+				nx, ny := cx+d.X, cy+d.Y
+				if nx < -1 || ny < -1 || nx > g.field.Width || ny > g.field.Height {
+					continue
+				}
+				nIdx := g.index(nx, ny)
+				if labels[nIdx] == 0 && g.isForeground(nx, ny) == foreground {
+					labels[nIdx] = label
+					queue = append(queue, nIdx)
+				}
+			}
+		}
+	}
+	return labels
+}
@@ -0,0 +1,149 @@
+package border
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"sort"
+	"testing"
+)
+
+// TestFindContoursMarchingSquares tests the sub-pixel points found for small fields.
+func TestFindContoursMarchingSquares(t *testing.T) {
+	testCases := []struct {
+		name           string
+		field          *Field
+		level          float64
+		expectedPoints [][]PointF
+		expectedTypes  []int
+		expectedParent []int
+		expectErr      error
+	}{
+		{
+			name:           "success single pixel",
+			field:          &Field{Width: 1, Height: 1, Values: []float64{1}},
+			level:          0.5,
+			expectedPoints: [][]PointF{{{0, -0.5}, {-0.5, 0}, {0, 0.5}, {0.5, 0}}},
+			expectedTypes:  []int{Outer},
+			expectedParent: []int{1},
+		},
+		{
+			name:           "success interpolated",
+			field:          &Field{Width: 3, Height: 1, Values: []float64{0, 1, 0.25}},
+			level:          0.5,
+			expectedPoints: [][]PointF{{{1, -0.5}, {0.5, 0}, {1, 0.5}, {1 + 2.0/3.0, 0}}},
+			expectedTypes:  []int{Outer},
+			expectedParent: []int{1},
+		},
+		{
+			name:  "success ring with hole",
+			field: &Field{Width: 3, Height: 3, Values: []float64{1, 1, 1, 1, 0, 1, 1, 1, 1}},
+			level: 0.5,
+			expectedPoints: [][]PointF{
+				{{0, -0.5}, {-0.5, 0}, {-0.5, 1}, {-0.5, 2}, {0, 2.5}, {1, 2.5}, {2, 2.5}, {2.5, 2}, {2.5, 1}, {2.5, 0}, {2, -0.5}, {1, -0.5}},
+				{{0.5, 1}, {1, 0.5}, {1.5, 1}, {1, 1.5}},
+			},
+			expectedTypes:  []int{Outer, Hole},
+			expectedParent: []int{1, 2},
+		},
+		{
+			name:           "success diagonal connected",
+			field:          &Field{Width: 2, Height: 2, Values: []float64{1, 0, 0, 1}},
+			level:          0.5,
+			expectedPoints: [][]PointF{{{0, -0.5}, {-0.5, 0}, {0, 0.5}, {0.5, 1}, {1, 1.5}, {1.5, 1}, {1, 0.5}, {0.5, 0}}},
+			expectedTypes:  []int{Outer},
+			expectedParent: []int{1},
+		},
+		{
+			name:           "success nothing above level",
+			field:          &Field{Width: 2, Height: 1, Values: []float64{0.2, math.NaN()}},
+			level:          0.5,
+			expectedPoints: [][]PointF{},
+		},
+		{
+			name:      "error values missing",
+			field:     &Field{Width: 2, Height: 2, Values: []float64{1}},
+			expectErr: ErrInvalidField,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			root, err := FindContoursMarchingSquares(tc.field, tc.level)
+			if tc.expectErr != nil {
+				if !errors.Is(err, tc.expectErr) {
+					t.Errorf("expected error %v, got %v", tc.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			contours := flattenContoursF(root)[1:]
+			if len(contours) != len(tc.expectedPoints) {
+				t.Fatalf("expected %d contours, got %d", len(tc.expectedPoints), len(contours))
+			}
+			for i, c := range contours {
+				if !slices.EqualFunc(c.Points, tc.expectedPoints[i], func(a PointF, b PointF) bool {
+					return math.Abs(a.X-b.X) < measureTolerance && math.Abs(a.Y-b.Y) < measureTolerance
+				}) {
+					t.Errorf("contour %d: expected points %v, got %v", c.Id, tc.expectedPoints[i], c.Points)
+				}
+				if c.BorderType != tc.expectedTypes[i] || c.ParentId != tc.expectedParent[i] {
+					t.Errorf("contour %d: expected type %d parent %d, got type %d parent %d", c.Id, tc.expectedTypes[i], tc.expectedParent[i], c.BorderType, c.ParentId)
+				}
+			}
+		})
+	}
+}
+
+// TestFindContoursMarchingSquaresMatchesFindContours tests a binary field gives the same tree as FindContours.
+func TestFindContoursMarchingSquaresMatchesFindContours(t *testing.T) {
+	for _, filename := range []string{`../testimages/unittest1.png`, `../testimages/image1.png`, `../testimages/image2.png`, `../testimages/florida.png`} {
+		t.Run(filename, func(t *testing.T) {
+			img, err := LoadImage(filename, 0, 0)
+			if err != nil {
+				t.Fatalf("Unable to load test image: %s", err.Error())
+			}
+
+			field := NewFieldFromSuzukiImage(img)
+			expected, err := FindContours(img)
+			if err != nil {
+				t.Fatalf("Unable to find contours: %s", err.Error())
+			}
+
+			root, err := FindContoursMarchingSquares(field, 0.5)
+			if err != nil {
+				t.Fatalf("Unable to find contours: %s", err.Error())
+			}
+
+			all := flattenContours(expected)
+			sort.Slice(all, func(i, j int) bool { return all[i].Id < all[j].Id })
+			allF := flattenContoursF(root)
+			sort.Slice(allF, func(i, j int) bool { return allF[i].Id < allF[j].Id })
+			if len(all) != len(allF) {
+				t.Fatalf("expected %d contours, got %d", len(all), len(allF))
+			}
+
+			for i, c := range allF {
+				e := all[i]
+				if c.Id != e.Id || c.ParentId != e.ParentId || c.BorderType != e.BorderType {
+					t.Errorf("expected contour %d type %d parent %d, got contour %d type %d parent %d", e.Id, e.BorderType, e.ParentId, c.Id, c.BorderType, c.ParentId)
+				}
+				if i > 0 && math.Signbit(c.SignedArea()) != (c.BorderType == Outer) {
+					t.Errorf("contour %d: expected outer borders anti-clockwise and holes clockwise", c.Id)
+				}
+			}
+		})
+	}
+}
+
+// flattenContoursF returns every contour in the tree, depth first.
+func flattenContoursF(c *ContourF) []*ContourF {
+	contours := []*ContourF{c}
+	for _, child := range c.Children {
+		contours = append(contours, flattenContoursF(child)...)
+	}
+	return contours
+}
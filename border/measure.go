@@ -0,0 +1,161 @@
+package border
+
+import (
+	"image"
+	"math"
+)
+
+// Measurements treat the contour as a polygon through the centre of each border pixel (as per OpenCV's
+// contourArea/arcLength), so a contour around a 3x3 block of pixels has an area of 4 rather than 9.
+
+// SignedArea returns the area of the polygon formed by the contour points.
+// It is positive if the points are clockwise when displayed (ie. with y pointing down) and negative if
+// anti-clockwise. FindContours traces Outer borders anti-clockwise and Holes clockwise.
+func (c *Contour) SignedArea() float64 {
+	n := len(c.Points)
+	if n < 3 {
+		return 0
+	}
+
+	sum := 0
+	for i, p := range c.Points {
+		next := c.Points[(i+1)%n]
+		sum += p.X*next.Y - next.X*p.Y
+	}
+	return float64(sum) / 2.0
+}
+
+// Area returns the area of the polygon formed by the contour points.
+func (c *Contour) Area() float64 {
+	return math.Abs(c.SignedArea())
+}
+
+// Perimeter returns the length of the closed polygon formed by the contour points.
+func (c *Contour) Perimeter() float64 {
+	n := len(c.Points)
+	if n < 2 {
+		return 0
+	}
+
+	perimeter := 0.0
+	for i, p := range c.Points {
+		next := c.Points[(i+1)%n]
+		perimeter += math.Hypot(float64(next.X-p.X), float64(next.Y-p.Y))
+	}
+	return perimeter
+}
+
+// Centroid returns the centre of mass of the polygon formed by the contour points.
+// If the polygon has no area (eg. a single pixel or a line) the mean of the points is returned instead.
+func (c *Contour) Centroid() (float64, float64) {
+	area, cx, cy := c.weightedCentroid()
+	if area == 0 {
+		return c.meanPoint()
+	}
+	return cx / area, cy / area
+}
+
+// Bounds returns the smallest rectangle containing all of the contour points.
+// As per image.Rectangle, Max is exclusive, so a single point p has bounds of p to p+(1,1).
+func (c *Contour) Bounds() image.Rectangle {
+	if len(c.Points) == 0 {
+		return image.Rectangle{}
+	}
+
+	r := image.Rectangle{Min: c.Points[0], Max: c.Points[0]}
+	for _, p := range c.Points[1:] {
+		r.Min.X = min(r.Min.X, p.X)
+		r.Min.Y = min(r.Min.Y, p.Y)
+		r.Max.X = max(r.Max.X, p.X)
+		r.Max.Y = max(r.Max.Y, p.Y)
+	}
+	r.Max = r.Max.Add(image.Point{1, 1})
+	return r
+}
+
+// NetArea returns the area of the contour minus the area of any holes directly inside it.
+func (c *Contour) NetArea() float64 {
+	area := c.Area()
+	for _, hole := range c.holes() {
+		area -= hole.Area()
+	}
+	return area
+}
+
+// NetPerimeter returns the perimeter of the contour plus the perimeters of any holes directly inside it.
+func (c *Contour) NetPerimeter() float64 {
+	perimeter := c.Perimeter()
+	for _, hole := range c.holes() {
+		perimeter += hole.Perimeter()
+	}
+	return perimeter
+}
+
+// NetCentroid returns the centre of mass of the contour with any holes directly inside it removed.
+// If the remaining area is 0, Centroid is returned.
+func (c *Contour) NetCentroid() (float64, float64) {
+	area, mx, my := c.weightedCentroid()
+	for _, hole := range c.holes() {
+		holeArea, hx, hy := hole.weightedCentroid()
+		area -= holeArea
+		mx -= hx
+		my -= hy
+	}
+
+	if area <= 0 {
+		return c.Centroid()
+	}
+	return mx / area, my / area
+}
+
+// holes returns the children of the contour that are holes.
+func (c *Contour) holes() []*Contour {
+	holes := []*Contour{}
+	for _, child := range c.Children {
+		if child.BorderType == Hole {
+			holes = append(holes, child)
+		}
+	}
+	return holes
+}
+
+// weightedCentroid returns the area of the contour along with the centroid multiplied by the area.
+func (c *Contour) weightedCentroid() (float64, float64, float64) {
+	n := len(c.Points)
+	if n < 3 {
+		return 0, 0, 0
+	}
+
+	sum := 0.0
+	mx := 0.0
+	my := 0.0
+	for i, p := range c.Points {
+		next := c.Points[(i+1)%n]
+		cross := float64(p.X*next.Y - next.X*p.Y)
+		sum += cross
+		mx += float64(p.X+next.X) * cross
+		my += float64(p.Y+next.Y) * cross
+	}
+
+	// sum is twice the signed area, so dividing by 3*sum gives the centroid. Scale that by the unsigned area.
+	if sum == 0 {
+		return 0, 0, 0
+	}
+	area := math.Abs(sum) / 2.0
+	return area, mx / (3 * sum) * area, my / (3 * sum) * area
+}
+
+// meanPoint returns the mean of the contour points.
+func (c *Contour) meanPoint() (float64, float64) {
+	if len(c.Points) == 0 {
+		return 0, 0
+	}
+
+	sumX := 0
+	sumY := 0
+	for _, p := range c.Points {
+		sumX += p.X
+		sumY += p.Y
+	}
+	return float64(sumX) / float64(len(c.Points)), float64(sumY) / float64(len(c.Points))
+}
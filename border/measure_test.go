@@ -0,0 +1,159 @@
+package border
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"github.com/kpfaulkner/borders/common"
+)
+
+const measureTolerance = 0.000001
+
+// TestContourMeasurements tests area, perimeter, centroid and bounds of simple shapes.
+func TestContourMeasurements(t *testing.T) {
+	testCases := []struct {
+		name   string
+		points []image.Point
+
+		expectedSignedArea float64
+		expectedPerimeter  float64
+		expectedCentroidX  float64
+		expectedCentroidY  float64
+		expectedBounds     image.Rectangle
+	}{
+		{
+			name:               "success anti-clockwise square",
+			points:             []image.Point{{0, 0}, {0, 4}, {4, 4}, {4, 0}},
+			expectedSignedArea: -16,
+			expectedPerimeter:  16,
+			expectedCentroidX:  2,
+			expectedCentroidY:  2,
+			expectedBounds:     image.Rect(0, 0, 5, 5),
+		},
+		{
+			name:               "success clockwise triangle",
+			points:             []image.Point{{0, 0}, {6, 0}, {0, 3}},
+			expectedSignedArea: 9,
+			expectedPerimeter:  9 + math.Sqrt(45),
+			expectedCentroidX:  2,
+			expectedCentroidY:  1,
+			expectedBounds:     image.Rect(0, 0, 7, 4),
+		},
+		{
+			name:              "success single point",
+			points:            []image.Point{{2, 3}},
+			expectedCentroidX: 2,
+			expectedCentroidY: 3,
+			expectedBounds:    image.Rect(2, 3, 3, 4),
+		},
+		{
+			name:              "success line",
+			points:            []image.Point{{0, 0}, {3, 0}},
+			expectedPerimeter: 6,
+			expectedCentroidX: 1.5,
+			expectedBounds:    image.Rect(0, 0, 4, 1),
+		},
+		{
+			name: "success no points",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := NewContour(2)
+			c.Points = tc.points
+
+			if math.Abs(c.SignedArea()-tc.expectedSignedArea) > measureTolerance {
+				t.Errorf("expected signed area %f, got %f", tc.expectedSignedArea, c.SignedArea())
+			}
+
+			if math.Abs(c.Area()-math.Abs(tc.expectedSignedArea)) > measureTolerance {
+				t.Errorf("expected area %f, got %f", math.Abs(tc.expectedSignedArea), c.Area())
+			}
+
+			if math.Abs(c.Perimeter()-tc.expectedPerimeter) > measureTolerance {
+				t.Errorf("expected perimeter %f, got %f", tc.expectedPerimeter, c.Perimeter())
+			}
+
+			x, y := c.Centroid()
+			if math.Abs(x-tc.expectedCentroidX) > measureTolerance || math.Abs(y-tc.expectedCentroidY) > measureTolerance {
+				t.Errorf("expected centroid %f,%f got %f,%f", tc.expectedCentroidX, tc.expectedCentroidY, x, y)
+			}
+
+			if c.Bounds() != tc.expectedBounds {
+				t.Errorf("expected bounds %v, got %v", tc.expectedBounds, c.Bounds())
+			}
+		})
+	}
+}
+
+// TestContourNetMeasurements tests holes are removed from the aggregate measurements.
+func TestContourNetMeasurements(t *testing.T) {
+	testCases := []struct {
+		name    string
+		contour func() *Contour
+
+		expectedNetArea      float64
+		expectedNetPerimeter float64
+		expectedCentroidX    float64
+		expectedCentroidY    float64
+	}{
+		{
+			name: "success square with hole",
+			contour: func() *Contour {
+				outer := NewContour(2)
+				outer.BorderType = Outer
+				outer.Points = []image.Point{{0, 0}, {0, 10}, {10, 10}, {10, 0}}
+				hole := NewContour(3)
+				hole.Points = []image.Point{{2, 2}, {4, 2}, {4, 4}, {2, 4}}
+				outer.Children = append(outer.Children, hole)
+				return outer
+			},
+			expectedNetArea:      96,
+			expectedNetPerimeter: 48,
+			expectedCentroidX:    (5*100 - 3*4) / 96.0,
+			expectedCentroidY:    (5*100 - 3*4) / 96.0,
+		},
+		{
+			name: "success found by FindContours",
+			contour: func() *Contour {
+				data := make([]int, 7*7)
+				for y := 1; y <= 5; y++ {
+					for x := 1; x <= 5; x++ {
+						data[y*7+x] = 1
+					}
+				}
+				data[3*7+3] = 0
+				root, err := FindContours(common.NewSuzukiImageFromData(7, 7, false, data))
+				if err != nil {
+					t.Fatalf("Unable to find contours: %s", err.Error())
+				}
+				return root.Children[0]
+			},
+			expectedNetArea:      16 - 2,
+			expectedNetPerimeter: 16 + 4*math.Sqrt2,
+			expectedCentroidX:    3,
+			expectedCentroidY:    3,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := tc.contour()
+
+			if math.Abs(c.NetArea()-tc.expectedNetArea) > measureTolerance {
+				t.Errorf("expected net area %f, got %f", tc.expectedNetArea, c.NetArea())
+			}
+
+			if math.Abs(c.NetPerimeter()-tc.expectedNetPerimeter) > measureTolerance {
+				t.Errorf("expected net perimeter %f, got %f", tc.expectedNetPerimeter, c.NetPerimeter())
+			}
+
+			x, y := c.NetCentroid()
+			if math.Abs(x-tc.expectedCentroidX) > measureTolerance || math.Abs(y-tc.expectedCentroidY) > measureTolerance {
+				t.Errorf("expected centroid %f,%f got %f,%f", tc.expectedCentroidX, tc.expectedCentroidY, x, y)
+			}
+		})
+	}
+}
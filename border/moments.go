@@ -0,0 +1,146 @@
+package border
+
+import (
+	"math"
+)
+
+const (
+	// epsilons used by OpenCV (FLT_EPSILON and DBL_EPSILON) to detect zero area.
+	float32Epsilon = 1.1920929e-07
+	float64Epsilon = 2.220446049250313e-16
+)
+
+// Moments are the spatial (M), central (Mu) and normalised central (Nu) moments of a contour up to
+// the third order. They match the values returned by OpenCV's cv::moments for the same points.
+type Moments struct {
+	M00, M10, M01, M20, M11, M02, M30, M21, M12, M03 float64
+	Mu20, Mu11, Mu02, Mu30, Mu21, Mu12, Mu03         float64
+	Nu20, Nu11, Nu02, Nu30, Nu21, Nu12, Nu03         float64
+}
+
+// Moments calculates the moments of the polygon formed by the contour points.
+// The moments are the same regardless of whether the contour is clockwise or anti-clockwise, and are all
+// 0 for contours with no area. Holes inside the contour are not subtracted.
+func (c *Contour) Moments() Moments {
+	m := Moments{}
+	n := len(c.Points)
+	if n == 0 {
+		return m
+	}
+
+	// based on OpenCV's contourMoments, which uses Green's theorem to integrate along the polygon edges.
+	var a00, a10, a01, a20, a11, a02, a30, a21, a12, a03 float64
+	prevX := float64(c.Points[n-1].X)
+	prevY := float64(c.Points[n-1].Y)
+	prevX2 := prevX * prevX
+	prevY2 := prevY * prevY
+	for _, p := range c.Points {
+		xi := float64(p.X)
+		yi := float64(p.Y)
+		xi2 := xi * xi
+		yi2 := yi * yi
+		dxy := prevX*yi - xi*prevY
+		sumX := prevX + xi
+		sumY := prevY + yi
+
+		a00 += dxy
+		a10 += dxy * sumX
+		a01 += dxy * sumY
+		a20 += dxy * (prevX*sumX + xi2)
+		a11 += dxy * (prevX*(sumY+prevY) + xi*(sumY+yi))
+		a02 += dxy * (prevY*sumY + yi2)
+		a30 += dxy * sumX * (prevX2 + xi2)
+		a03 += dxy * sumY * (prevY2 + yi2)
+		a21 += dxy * (prevX2*(3*prevY+yi) + 2*xi*prevX*sumY + xi2*(prevY+3*yi))
+		a12 += dxy * (prevY2*(3*prevX+xi) + 2*yi*prevY*sumX + yi2*(prevX+3*xi))
+
+		prevX = xi
+		prevY = yi
+		prevX2 = xi2
+		prevY2 = yi2
+	}
+
+	if math.Abs(a00) <= float32Epsilon {
+		return m
+	}
+
+	// flip the sign for anti-clockwise contours so the area is always positive.
+	sign := 1.0
+	if a00 < 0 {
+		sign = -1.0
+	}
+	m.M00 = sign * a00 / 2
+	m.M10 = sign * a10 / 6
+	m.M01 = sign * a01 / 6
+	m.M20 = sign * a20 / 12
+	m.M11 = sign * a11 / 24
+	m.M02 = sign * a02 / 12
+	m.M30 = sign * a30 / 20
+	m.M21 = sign * a21 / 60
+	m.M12 = sign * a12 / 60
+	m.M03 = sign * a03 / 20
+
+	m.completeMomentState()
+	return m
+}
+
+// completeMomentState calculates the central and normalised moments from the spatial moments.
+func (m *Moments) completeMomentState() {
+	cx := 0.0
+	cy := 0.0
+	invM00 := 0.0
+	if math.Abs(m.M00) > float64Epsilon {
+		invM00 = 1.0 / m.M00
+		cx = m.M10 * invM00
+		cy = m.M01 * invM00
+	}
+
+	m.Mu20 = m.M20 - m.M10*cx
+	m.Mu11 = m.M11 - m.M10*cy
+	m.Mu02 = m.M02 - m.M01*cy
+	m.Mu30 = m.M30 - cx*(3*m.Mu20+cx*m.M10)
+	m.Mu21 = m.M21 - cx*(2*m.Mu11+cx*m.M01) - cy*m.Mu20
+	m.Mu12 = m.M12 - cy*(2*m.Mu11+cy*m.M10) - cx*m.Mu02
+	m.Mu03 = m.M03 - cy*(3*m.Mu02+cy*m.M01)
+
+	invSqrtM00 := math.Sqrt(math.Abs(invM00))
+	s2 := invM00 * invM00
+	s3 := s2 * invSqrtM00
+	m.Nu20 = m.Mu20 * s2
+	m.Nu11 = m.Mu11 * s2
+	m.Nu02 = m.Mu02 * s2
+	m.Nu30 = m.Mu30 * s3
+	m.Nu21 = m.Mu21 * s3
+	m.Nu12 = m.Mu12 * s3
+	m.Nu03 = m.Mu03 * s3
+}
+
+// HuMoments returns the seven Hu moment invariants, which are invariant to translation, scale and rotation
+// (the seventh changes sign for reflections). Matches OpenCV's cv::HuMoments.
+func (m Moments) HuMoments() [7]float64 {
+	var hu [7]float64
+
+	t0 := m.Nu30 + m.Nu12
+	t1 := m.Nu21 + m.Nu03
+	q0 := t0 * t0
+	q1 := t1 * t1
+	n4 := 4 * m.Nu11
+	s := m.Nu20 + m.Nu02
+	d := m.Nu20 - m.Nu02
+
+	hu[0] = s
+	hu[1] = d*d + n4*m.Nu11
+	hu[3] = q0 + q1
+	hu[5] = d*(q0-q1) + n4*t0*t1
+
+	t0 *= q0 - 3*q1
+	t1 *= 3*q0 - q1
+
+	q0 = m.Nu30 - 3*m.Nu12
+	q1 = 3*m.Nu21 - m.Nu03
+
+	hu[2] = q0*q0 + q1*q1
+	hu[4] = q0*t0 + q1*t1
+	hu[6] = q1*t0 - q0*t1
+	return hu
+}
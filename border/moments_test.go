@@ -0,0 +1,266 @@
+package border
+
+import (
+	"encoding/json"
+	"image"
+	"math"
+	"os"
+	"testing"
+)
+
+// TestContourMoments tests moments against the analytic values for simple shapes (which OpenCV's cv::moments
+// also returns exactly, as it integrates the polygon rather than the pixels).
+func TestContourMoments(t *testing.T) {
+	rectangle := Moments{
+		M00: 8, M10: 16, M01: 8, M20: 128.0 / 3, M11: 16, M02: 32.0 / 3, M30: 128, M21: 256.0 / 6, M12: 128.0 / 6, M03: 16,
+		Mu20: 128.0 / 12, Mu02: 32.0 / 12,
+		Nu20: 128.0 / 12 / 64, Nu02: 32.0 / 12 / 64,
+	}
+
+	// right angled triangle (0,0), (6,0), (0,3).
+	triangle := Moments{
+		M00: 9, M10: 18, M01: 9, M20: 54, M11: 13.5, M02: 13.5, M30: 194.4, M21: 32.4, M12: 16.2, M03: 24.3,
+		Mu20: 18, Mu11: -4.5, Mu02: 4.5, Mu30: 14.4, Mu21: -3.6, Mu12: -1.8, Mu03: 1.8,
+		Nu20: 18.0 / 81, Nu11: -4.5 / 81, Nu02: 4.5 / 81, Nu30: 14.4 / 243, Nu21: -3.6 / 243, Nu12: -1.8 / 243, Nu03: 1.8 / 243,
+	}
+
+	testCases := []struct {
+		name     string
+		points   []image.Point
+		expected Moments
+	}{
+		{
+			name:     "success rectangle",
+			points:   []image.Point{{0, 0}, {4, 0}, {4, 2}, {0, 2}},
+			expected: rectangle,
+		},
+		{
+			name:     "success rectangle anti-clockwise",
+			points:   []image.Point{{0, 0}, {0, 2}, {4, 2}, {4, 0}},
+			expected: rectangle,
+		},
+		{
+			name:     "success triangle",
+			points:   []image.Point{{0, 0}, {6, 0}, {0, 3}},
+			expected: triangle,
+		},
+		{
+			name:   "success line has no moments",
+			points: []image.Point{{0, 0}, {3, 0}, {6, 0}},
+		},
+		{
+			name: "success no points",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := NewContour(2)
+			c.Points = tc.points
+
+			got := c.Moments()
+			expected := []float64{tc.expected.M00, tc.expected.M10, tc.expected.M01, tc.expected.M20, tc.expected.M11, tc.expected.M02, tc.expected.M30, tc.expected.M21, tc.expected.M12, tc.expected.M03, tc.expected.Mu20, tc.expected.Mu11, tc.expected.Mu02, tc.expected.Mu30, tc.expected.Mu21, tc.expected.Mu12, tc.expected.Mu03, tc.expected.Nu20, tc.expected.Nu11, tc.expected.Nu02, tc.expected.Nu30, tc.expected.Nu21, tc.expected.Nu12, tc.expected.Nu03}
+			actual := []float64{got.M00, got.M10, got.M01, got.M20, got.M11, got.M02, got.M30, got.M21, got.M12, got.M03, got.Mu20, got.Mu11, got.Mu02, got.Mu30, got.Mu21, got.Mu12, got.Mu03, got.Nu20, got.Nu11, got.Nu02, got.Nu30, got.Nu21, got.Nu12, got.Nu03}
+			for i := range expected {
+				if math.Abs(expected[i]-actual[i]) > measureTolerance {
+					t.Fatalf("expected moments %+v, got %+v", tc.expected, got)
+				}
+			}
+		})
+	}
+}
+
+// TestHuMoments tests the Hu invariants of known shapes and that they are invariant to translation,
+// scale and rotation.
+func TestHuMoments(t *testing.T) {
+	triangle := []image.Point{{0, 0}, {6, 0}, {1, 3}}
+
+	transform := func(points []image.Point, f func(p image.Point) image.Point) []image.Point {
+		transformed := []image.Point{}
+		for _, p := range points {
+			transformed = append(transformed, f(p))
+		}
+		return transformed
+	}
+
+	hu := func(points []image.Point) [7]float64 {
+		c := NewContour(2)
+		c.Points = points
+		return c.Moments().HuMoments()
+	}
+
+	testCases := []struct {
+		name     string
+		points   []image.Point
+		expected [7]float64
+
+		// seventh invariant changes sign for reflections.
+		reflected bool
+	}{
+		{
+			name:     "success square",
+			points:   []image.Point{{0, 0}, {0, 5}, {5, 5}, {5, 0}},
+			expected: [7]float64{1.0 / 6},
+		},
+		{
+			name:     "success rectangle",
+			points:   []image.Point{{0, 0}, {4, 0}, {4, 2}, {0, 2}},
+			expected: [7]float64{1.0/6 + 1.0/24, 0.125 * 0.125},
+		},
+		{
+			name: "success translated, scaled and rotated triangle",
+			points: transform(triangle, func(p image.Point) image.Point {
+				return image.Point{-3*p.Y + 10, 3*p.X + 20}
+			}),
+			expected: hu(triangle),
+		},
+		{
+			name: "success reflected triangle",
+			points: transform(triangle, func(p image.Point) image.Point {
+				return image.Point{-p.X, p.Y}
+			}),
+			expected:  hu(triangle),
+			reflected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := hu(tc.points)
+			expected := tc.expected
+			if tc.reflected {
+				expected[6] *= -1
+			}
+
+			// the higher invariants are very small, so use a tighter tolerance than the other measurements.
+			for i := range expected {
+				if math.Abs(expected[i]-got[i]) > 1e-12 {
+					t.Fatalf("expected %v, got %v", expected, got)
+				}
+			}
+		})
+	}
+}
+
+// TestMomentsIrregular tests moments and Hu invariants of a non-convex contour and the outer border and hole of
+// an image (whose contours match OpenCV's findContours, see opencv-sanity-check/results). The expected values
+// were integrated exactly over the polygons (triangle by triangle, with rational arithmetic), independently of
+// the edge formulas used by Moments.
+func TestMomentsIrregular(t *testing.T) {
+	img, err := LoadImage(`../opencv-sanity-check/test.png`, 0, 0)
+	if err != nil {
+		t.Fatalf("Unable to load test image: %s", err.Error())
+	}
+	root, err := FindContours(img)
+	if err != nil {
+		t.Fatalf("Unable to find contours: %s", err.Error())
+	}
+	outer := root.Children[0]
+	hole := outer.Children[0]
+
+	testCases := []struct {
+		name   string
+		points []image.Point
+		m      []float64
+		mu     []float64
+		nu     []float64
+		hu     []float64
+	}{
+		{
+			name:   "success non-convex",
+			points: []image.Point{{0, 0}, {5, 1}, {7, 6}, {3, 4}, {1, 7}, {-2, 3}},
+			m:      []float64{33.5, 71.16666666666667, 103.0, 297.4166666666667, 227.95833333333334, 389.4166666666667, 1340.95, 995.4666666666667, 870.4333333333333, 1658.6},
+			mu:     []float64{146.23175787728027, 9.147388059701493, 72.73009950248756, 87.82145326435814, 42.15637112942749, -13.084518947550803, 14.053218979728225},
+			nu:     []float64{0.13030230151684585, 0.008150936119136995, 0.06480739541322127, 0.013520370784361429, 0.006490097207538789, -0.002014400139489452, 0.0021635343558686405},
+			hu:     []float64{0.19510969693006713, 0.00455533376399561, 0.000682257165298316, 0.00020727269971585697, 2.7496716800699964e-08, 7.012393156020855e-06, -7.29336968982422e-08},
+		},
+		{
+			name:   "success image outer border",
+			points: outer.Points,
+			m:      []float64{58565.0, 22635372.5, 16486047.5, 9091298731.666666, 6371857358.75, 4879186801.666667, 3778715132091.25, 2559200592964.1665, 1885805698844.1667, 1507690258993.75},
+			mu:     []float64{342727260.4166667, 0, 238364430.41666666, 0, 0, 0, 0},
+			nu:     []float64{0.09992458521870287, 0, 0.06949685534591195, 0, 0, 0, 0},
+			hu:     []float64{0.16942144056461483, 0.0009258467452115331, 0, 0, 0, 0, 0},
+		},
+		{
+			name:   "success image hole",
+			points: hole.Points,
+			m:      []float64{17301.0, 6652234.5, 4991338.5, 2587259789.6666665, 1919169653.25, 1461105019.0, 1017468144303.25, 746424449318.8334, 561794879805.5, 433705726211.25},
+			mu:     []float64{29475624.416666668, 0, 21103861.75, 0, 0, 0, 0},
+			nu:     []float64{0.09847377836032484, 0, 0.07050493571024588, 0, 0, 0, 0},
+			hu:     []float64{0.16897871407057072, 0.0007822561591848756, 0, 0, 0, 0, 0},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := NewContour(2)
+			c.Points = tc.points
+			m := c.Moments()
+			hu := m.HuMoments()
+
+			compareMoments(t, "m", []float64{m.M00, m.M10, m.M01, m.M20, m.M11, m.M02, m.M30, m.M21, m.M12, m.M03}, tc.m, tc.m)
+			compareMoments(t, "mu", []float64{m.Mu20, m.Mu11, m.Mu02, m.Mu30, m.Mu21, m.Mu12, m.Mu03}, tc.mu, []float64{tc.m[3], tc.m[4], tc.m[5], tc.m[6], tc.m[7], tc.m[8], tc.m[9]})
+			compareMoments(t, "nu", []float64{m.Nu20, m.Nu11, m.Nu02, m.Nu30, m.Nu21, m.Nu12, m.Nu03}, tc.nu, nil)
+			compareMoments(t, "hu", hu[:], tc.hu, nil)
+		})
+	}
+}
+
+// TestMomentsOpenCVReference tests moments and Hu invariants against OpenCV's cv::moments and cv::HuMoments for the
+// irregular contour and the OpenCV contours of opencv-sanity-check/test.png, as generated by
+// opencv-sanity-check/moments.py (the generator of each entry records how).
+func TestMomentsOpenCVReference(t *testing.T) {
+	data, err := os.ReadFile(`../opencv-sanity-check/results/opencv/moments.json`)
+	if err != nil {
+		t.Fatalf("Unable to read reference values: %s", err.Error())
+	}
+
+	references := []struct {
+		Name    string             `json:"name"`
+		Points  [][2]int           `json:"points"`
+		Moments map[string]float64 `json:"moments"`
+		Hu      []float64          `json:"hu"`
+	}{}
+	if err := json.Unmarshal(data, &references); err != nil {
+		t.Fatalf("Unable to parse reference values: %s", err.Error())
+	}
+	if len(references) < 3 {
+		t.Fatalf("expected the irregular and test.png reference contours, got %d", len(references))
+	}
+
+	for _, ref := range references {
+		t.Run(ref.Name, func(t *testing.T) {
+			c := NewContour(2)
+			for _, p := range ref.Points {
+				c.Points = append(c.Points, image.Point{p[0], p[1]})
+			}
+			m := c.Moments()
+			hu := m.HuMoments()
+
+			r := ref.Moments
+			spatial := []float64{r["m00"], r["m10"], r["m01"], r["m20"], r["m11"], r["m02"], r["m30"], r["m21"], r["m12"], r["m03"]}
+			compareMoments(t, "m", []float64{m.M00, m.M10, m.M01, m.M20, m.M11, m.M02, m.M30, m.M21, m.M12, m.M03}, spatial, spatial)
+			compareMoments(t, "mu", []float64{m.Mu20, m.Mu11, m.Mu02, m.Mu30, m.Mu21, m.Mu12, m.Mu03}, []float64{r["mu20"], r["mu11"], r["mu02"], r["mu30"], r["mu21"], r["mu12"], r["mu03"]}, spatial[3:])
+			compareMoments(t, "nu", []float64{m.Nu20, m.Nu11, m.Nu02, m.Nu30, m.Nu21, m.Nu12, m.Nu03}, []float64{r["nu20"], r["nu11"], r["nu02"], r["nu30"], r["nu21"], r["nu12"], r["nu03"]}, nil)
+			compareMoments(t, "hu", hu[:], ref.Hu, nil)
+		})
+	}
+}
+
+// compareMoments checks each moment is within a relative tolerance of the expected value. Central moments lose
+// precision relative to the spatial moments they are calculated from, so scale (if not nil) gives the magnitude
+// to use for each tolerance instead.
+func compareMoments(t *testing.T, name string, got []float64, expected []float64, scale []float64) {
+	t.Helper()
+	for i := range expected {
+		magnitude := math.Max(1, math.Abs(expected[i]))
+		if scale != nil {
+			magnitude = math.Max(magnitude, math.Abs(scale[i]))
+		}
+		if math.Abs(got[i]-expected[i]) > 1e-9*magnitude {
+			t.Errorf("expected %s %v, got %v", name, expected, got)
+			return
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package border
+
+import (
+	"image"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/kpfaulkner/borders/common"
+)
+
+// blob is an independent region of the image. It is a top level component along with everything inside
+// its holes, so can be traced without reference to the rest of the image.
+type blob struct {
+	id     int
+	bounds image.Rectangle
+
+	// sub is the blob copied into its own image, with crop being where it came from in the original.
+	sub  *common.SuzukiImage
+	crop image.Rectangle
+
+	// contours found within the blob, and the error encountered finding them.
+	root *Contour
+	err  error
+}
+
+// FindContoursParallel finds the same contours as FindContours, but traces independent regions of the image
+// concurrently using the requested number of workers (0 means runtime.NumCPU()).
+//
+// The image is partitioned into regions that are separated by the background connected to the edge of the
+// image, so each region is a top level blob plus everything nested inside it. Each region is traced separately
+// and the results merged, with the contour Ids reassigned so the resulting tree (Ids, parents, children and
+// points) is identical to the one generated by FindContours.
+//
+// Unlike FindContours, the supplied image is not modified. The image is only read from the calling goroutine,
+// so images that are not safe for concurrent use (eg. common.PagedStorage) are supported.
+func FindContoursParallel(img *common.SuzukiImage, workers int) (*Contour, error) {
+
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	// border following does not treat foreground on the edge of the image as touching the outside, so the
+	// partitioning below does not apply. Images generated by LoadImage are always padded to avoid this.
+	if hasForegroundOnEdge(img) {
+		return FindContours(img.Clone())
+	}
+
+	regions, blobs := partitionImage(img)
+
+	jobs := make(chan *blob)
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for b := range jobs {
+				b.root, b.err = findBlobContours(b)
+			}
+		}()
+	}
+
+	// blobs are copied here rather than by the workers, as reading the image may not be safe concurrently.
+	// Only the blobs being worked on are held in memory.
+	for _, b := range blobs {
+		copyBlob(img, regions, b)
+		jobs <- b
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, b := range blobs {
+		if b.err != nil {
+			return nil, b.err
+		}
+	}
+
+	root := mergeBlobContours(blobs)
+
+	// image was padded... so now shift every co-ord by -1,-1
+	if img.HasPadding() {
+		offsetContour(root, image.Point{-1, -1})
+	}
+	return root, nil
+}
+
+// hasForegroundOnEdge checks if any pixel on the edge of the image is populated.
+func hasForegroundOnEdge(img *common.SuzukiImage) bool {
+	for x := 0; x < img.Width; x++ {
+		if img.GetXY(x, 0) != 0 || img.GetXY(x, img.Height-1) != 0 {
+			return true
+		}
+	}
+	for y := 0; y < img.Height; y++ {
+		if img.GetXY(0, y) != 0 || img.GetXY(img.Width-1, y) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// partitionImage labels every pixel with the blob it belongs to (0 is the background connected to the edge
+// of the image). The background is 4 connected and the blobs 8 connected, matching the border following.
+// Blobs are returned in the order they are first encountered in a raster scan.
+func partitionImage(img *common.SuzukiImage) ([]int32, []*blob) {
+	width := img.Width
+	height := img.Height
+
+	const unvisited = -1
+	regions := make([]int32, width*height)
+	for i := range regions {
+		regions[i] = unvisited
+	}
+
+	// flood fill the outside background from the edges.
+	queue := []int{}
+	for idx := range regions {
+		x, y := idx%width, idx/width
+		if (x == 0 || y == 0 || x == width-1 || y == height-1) && img.GetXY(x, y) == 0 {
+			regions[idx] = 0
+			queue = append(queue, idx)
+		}
+	}
+	floodFill(img, regions, queue, 0, dirDelta4, func(x int, y int) bool {
+		return img.GetXY(x, y) == 0
+	})
+
+	// everything else is part of a blob.
+	blobs := []*blob{}
+	for idx := range regions {
+		if regions[idx] != unvisited {
+			continue
+		}
+
+		b := &blob{id: len(blobs) + 1}
+		regions[idx] = int32(b.id)
+		b.bounds = floodFill(img, regions, []int{idx}, int32(b.id), dirDelta, func(x int, y int) bool {
+			return true
+		})
+		blobs = append(blobs, b)
+	}
+
+	return regions, blobs
+}
+
+// dirDelta4 is the 4 connected subset of dirDelta.
+var dirDelta4 = []image.Point{{0, -1}, {1, 0}, {0, 1}, {-1, 0}}
+
+// floodFill sets the region of all unvisited pixels reachable from queue (using neighbours) for which include
+// returns true. Returns the bounds of the filled area.
+func floodFill(img *common.SuzukiImage, regions []int32, queue []int, region int32, neighbours []image.Point, include func(x int, y int) bool) image.Rectangle {
+	width := img.Width
+	height := img.Height
+
+	bounds := image.Rectangle{}
+	for len(queue) > 0 {
+		idx := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		p := image.Point{idx % width, idx / width}
+		bounds = bounds.Union(image.Rectangle{p, p.Add(image.Point{1, 1})})
+
+		for _, d := range neighbours {
+			n := p.Add(d)
+			if n.X < 0 || n.Y < 0 || n.X >= width || n.Y >= height {
+				continue
+			}
+			nIdx := n.Y*width + n.X
+			if regions[nIdx] == -1 && include(n.X, n.Y) {
+				regions[nIdx] = region
+				queue = append(queue, nIdx)
+			}
+		}
+	}
+	return bounds
+}
+
+// copyBlob copies the blob (with a 1 pixel margin of background) into its own image.
+func copyBlob(img *common.SuzukiImage, regions []int32, b *blob) {
+	b.crop = b.bounds.Inset(-1).Intersect(image.Rect(0, 0, img.Width, img.Height))
+	b.sub = common.NewSuzukiImage(b.crop.Dx(), b.crop.Dy(), false)
+	for y := b.crop.Min.Y; y < b.crop.Max.Y; y++ {
+		for x := b.crop.Min.X; x < b.crop.Max.X; x++ {
+			if regions[y*img.Width+x] == int32(b.id) && img.GetXY(x, y) != 0 {
+				b.sub.SetXY(x-b.crop.Min.X, y-b.crop.Min.Y, 1)
+			}
+		}
+	}
+}
+
+// findBlobContours finds the contours of the copied blob, returning them in the co-ords of the original image.
+// The copy is released once done.
+func findBlobContours(b *blob) (*Contour, error) {
+	root, err := FindContours(b.sub)
+	b.sub = nil
+	if err != nil {
+		return nil, err
+	}
+
+	offsetContour(root, b.crop.Min)
+	return root, nil
+}
+
+// mergeBlobContours combines the contours of every blob under a single root contour, reassigning the Ids
+// in the order FindContours would have found them (raster order of their starting point).
+func mergeBlobContours(blobs []*blob) *Contour {
+	type blobContour struct {
+		blob    int
+		contour *Contour
+	}
+
+	all := []blobContour{}
+	var collect func(b int, c *Contour)
+	collect = func(b int, c *Contour) {
+		for _, ch := range c.Children {
+			all = append(all, blobContour{b, ch})
+			collect(b, ch)
+		}
+	}
+	for i, b := range blobs {
+		collect(i, b.root)
+	}
+
+	sort.SliceStable(all, func(i, j int) bool {
+		pi, pj := all[i].contour.Points[0], all[j].contour.Points[0]
+		if pi.Y != pj.Y {
+			return pi.Y < pj.Y
+		}
+		return pi.X < pj.X
+	})
+
+	// old id to new id, for each blob. The blob root (1) is the new root.
+	ids := make([]map[int]int, len(blobs))
+	for i := range ids {
+		ids[i] = map[int]int{1: 1}
+	}
+	for i, bc := range all {
+		ids[bc.blob][bc.contour.Id] = i + 2
+	}
+
+	root := NewContour(1)
+	for _, bc := range all {
+		c := bc.contour
+		blobIds := ids[bc.blob]
+		c.Id = blobIds[c.Id]
+		c.ParentId = blobIds[c.ParentId]
+
+		conflicting := make(map[int]bool)
+		for id := range c.ConflictingContours {
+			conflicting[blobIds[id]] = true
+		}
+		c.ConflictingContours = conflicting
+
+		if c.Parent == blobs[bc.blob].root {
+			c.Parent = root
+			root.Children = append(root.Children, c)
+		}
+	}
+
+	return root
+}
@@ -0,0 +1,154 @@
+package border
+
+import (
+	"image"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/kpfaulkner/borders/common"
+)
+
+// TestFindContoursParallel checks the parallel contour tree is identical to the sequential one.
+func TestFindContoursParallel(t *testing.T) {
+	testCases := []struct {
+		name     string
+		filename string
+		opts     LoadOptions
+		workers  int
+	}{
+		{
+			name:     "success with nested contours",
+			filename: `../testimages/unittest1.png`,
+			opts:     LoadOptions{Erode: 1, Dilate: 1},
+			workers:  4,
+		},
+		{
+			name:     "success with many blobs",
+			filename: `../testimages/florida.png`,
+			workers:  3,
+		},
+		{
+			name:     "success with default workers",
+			filename: `../testimages/image1.png`,
+		},
+		{
+			name:     "success with single worker",
+			filename: `../testimages/testimage-perth-16.png`,
+			workers:  1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			img, err := LoadImageWithOptions(tc.filename, tc.opts)
+			if err != nil {
+				t.Fatalf("Unable to load test image: %s", err.Error())
+			}
+
+			original := img.Clone()
+			cont, err := FindContoursParallel(img, tc.workers)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if !img.Equals(original) {
+				t.Errorf("expected image to be unmodified")
+			}
+
+			expected, err := FindContours(img)
+			if err != nil {
+				t.Fatalf("Unable to find contours: %s", err.Error())
+			}
+
+			compareContourTrees(t, cont, expected)
+		})
+	}
+}
+
+// TestFindContoursParallelPaged checks images that are not safe for concurrent reads (paged storage mutates
+// its tile cache on every read) can be used. Run with -race to detect concurrent access.
+func TestFindContoursParallelPaged(t *testing.T) {
+	// grid of square blobs, each with a hole, so there are many blobs spread across many tiles.
+	loader := func(r image.Rectangle, dst []int32) error {
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				bx, by := x%20, y%20
+				if bx > 2 && bx < 15 && by > 2 && by < 15 && !(bx > 6 && bx < 10 && by > 6 && by < 10) {
+					dst[(y-r.Min.Y)*r.Dx()+x-r.Min.X] = 1
+				}
+			}
+		}
+		return nil
+	}
+
+	img, err := common.NewPagedSuzukiImage(200, 200, 16, 4, t.TempDir(), loader)
+	if err != nil {
+		t.Fatalf("Unable to create paged image: %s", err.Error())
+	}
+	defer img.Close()
+
+	dense := img.Clone()
+	cont, err := FindContoursParallel(img, 4)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := img.Err(); err != nil {
+		t.Fatalf("expected no paging error, got %v", err)
+	}
+
+	expected, err := FindContours(dense)
+	if err != nil {
+		t.Fatalf("Unable to find contours: %s", err.Error())
+	}
+
+	if len(expected.Children) != 100 {
+		t.Fatalf("expected 100 blobs, got %d", len(expected.Children))
+	}
+	compareContourTrees(t, cont, expected)
+}
+
+// compareContourTrees checks two contour trees are identical.
+func compareContourTrees(t *testing.T, got *Contour, expected *Contour) {
+	t.Helper()
+
+	if got.Id != expected.Id || got.ParentId != expected.ParentId || got.BorderType != expected.BorderType ||
+		got.ParentCollision != expected.ParentCollision || got.Usable != expected.Usable {
+		t.Fatalf("contour %d: expected %+v, got %+v", expected.Id, *expected, *got)
+	}
+
+	if !slices.Equal(got.Points, expected.Points) {
+		t.Fatalf("contour %d: expected points %v, got %v", expected.Id, expected.Points, got.Points)
+	}
+
+	if !maps.Equal(got.ConflictingContours, expected.ConflictingContours) {
+		t.Fatalf("contour %d: expected conflicts %v, got %v", expected.Id, expected.ConflictingContours, got.ConflictingContours)
+	}
+
+	if (got.Parent == nil) != (expected.Parent == nil) || (got.Parent != nil && got.Parent.Id != expected.Parent.Id) {
+		t.Fatalf("contour %d: parents differ", expected.Id)
+	}
+
+	if len(got.Children) != len(expected.Children) {
+		t.Fatalf("contour %d: expected %d children, got %d", expected.Id, len(expected.Children), len(got.Children))
+	}
+
+	for i := range expected.Children {
+		compareContourTrees(t, got.Children[i], expected.Children[i])
+	}
+}
+
+func BenchmarkFindContoursParallel(b *testing.B) {
+	img, err := LoadImage(`../testimages/florida.png`, 0, 0)
+	if err != nil {
+		b.Fatalf("Unable to load test image: %s", err.Error())
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err = FindContoursParallel(img, 0)
+		if err != nil {
+			b.Fatalf("Unable to find contours: %s", err.Error())
+		}
+	}
+}
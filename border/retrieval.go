@@ -0,0 +1,62 @@
+package border
+
+import (
+	"sort"
+)
+
+// Retrieval determines which contours are returned and how they are arranged.
+type Retrieval int
+
+const (
+	// RetrTree returns the full hierarchy of outer borders and holes.
+	RetrTree Retrieval = iota
+
+	// RetrExternal only returns the outer borders of the top level components, as children of the root.
+	// Holes and anything inside them are never traced, so this is quicker than the other modes.
+	RetrExternal
+
+	// RetrList returns every contour as a child of the root, with no hierarchy.
+	RetrList
+
+	// RetrCComp returns a two level hierarchy. Every outer border is a child of the root and every hole
+	// is a child of the outer border it is within.
+	RetrCComp
+)
+
+// arrangeContours rearranges the full tree under root for the retrieval mode.
+// Contours keep their Id, and children are ordered by Id (the order they were found).
+func arrangeContours(root *Contour, mode Retrieval) {
+	if mode != RetrList && mode != RetrCComp {
+		return
+	}
+
+	all := []*Contour{}
+	var collect func(c *Contour)
+	collect = func(c *Contour) {
+		for _, ch := range c.Children {
+			all = append(all, ch)
+			collect(ch)
+		}
+	}
+	collect(root)
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].Id < all[j].Id
+	})
+
+	for _, c := range all {
+		c.Children = nil
+	}
+	root.Children = nil
+
+	for _, c := range all {
+		parent := root
+		if mode == RetrCComp && c.BorderType == Hole && c.Parent != nil {
+			parent = c.Parent
+		}
+
+		c.Parent = parent
+		c.ParentId = parent.Id
+		c.ParentCollision = c.ConflictingContours[parent.Id]
+		parent.Children = append(parent.Children, c)
+	}
+}
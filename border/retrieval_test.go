@@ -0,0 +1,122 @@
+package border
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/kpfaulkner/borders/common"
+)
+
+// nestedRows is a thin ring and a thick ring, each containing other components.
+var nestedRows = []string{
+	"..................",
+	".#######..#######.",
+	".#.....#..#######.",
+	".#.###.#..##...##.",
+	".#.#.#.#..##.#.##.",
+	".#.###.#..##...##.",
+	".#.....#..#######.",
+	".#######..#######.",
+	"..................",
+	"..#.......###.....",
+	"..................",
+}
+
+// createRowsSuzukiImage creates a SuzukiImage where '#' is populated.
+func createRowsSuzukiImage(rows []string) *common.SuzukiImage {
+	si := common.NewSuzukiImage(len(rows[0]), len(rows), false)
+	for y, row := range rows {
+		for x, ch := range row {
+			if ch == '#' {
+				si.SetXY(x, y, 1)
+			}
+		}
+	}
+	return si
+}
+
+// TestFindContoursRetrieval tests each retrieval mode returns the expected subset/arrangement of the full tree.
+func TestFindContoursRetrieval(t *testing.T) {
+	images := map[string]func() (*common.SuzukiImage, error){
+		"nested": func() (*common.SuzukiImage, error) { return createRowsSuzukiImage(nestedRows), nil },
+	}
+	for _, filename := range []string{`../testimages/unittest1.png`, `../testimages/image1.png`, `../testimages/image2.png`, `../testimages/florida.png`} {
+		images[filename] = func() (*common.SuzukiImage, error) { return LoadImage(filename, 0, 0) }
+	}
+
+	for name, load := range images {
+		t.Run(name, func(t *testing.T) {
+			img, err := load()
+			if err != nil {
+				t.Fatalf("Unable to load test image: %s", err.Error())
+			}
+
+			tree, err := FindContours(img.Clone())
+			if err != nil {
+				t.Fatalf("Unable to find contours: %s", err.Error())
+			}
+			all := flattenContours(tree)[1:]
+			sort.Slice(all, func(i, j int) bool { return all[i].Id < all[j].Id })
+
+			find := func(mode Retrieval) *Contour {
+				root, err := FindContoursCtx(context.Background(), img.Clone(), FindOptions{Mode: mode})
+				if err != nil {
+					t.Fatalf("Unable to find contours: %s", err.Error())
+				}
+				return root
+			}
+
+			t.Run("external", func(t *testing.T) {
+				root := find(RetrExternal)
+				if len(root.Children) != len(tree.Children) {
+					t.Fatalf("expected %d contours, got %d", len(tree.Children), len(root.Children))
+				}
+				for i, c := range root.Children {
+					if c.ParentId != 1 || c.BorderType != Outer || len(c.Children) != 0 {
+						t.Errorf("contour %d: expected outer border with no children under the root", c.Id)
+					}
+					if !slices.Equal(c.Points, tree.Children[i].Points) {
+						t.Errorf("contour %d: expected points %v, got %v", c.Id, tree.Children[i].Points, c.Points)
+					}
+				}
+			})
+
+			t.Run("list", func(t *testing.T) {
+				root := find(RetrList)
+				if len(root.Children) != len(all) {
+					t.Fatalf("expected %d contours, got %d", len(all), len(root.Children))
+				}
+				for i, c := range root.Children {
+					if c.Id != all[i].Id || c.ParentId != 1 || c.Parent != root || len(c.Children) != 0 {
+						t.Errorf("contour %d: expected contour %d with no children under the root", c.Id, all[i].Id)
+					}
+					if !slices.Equal(c.Points, all[i].Points) {
+						t.Errorf("contour %d: expected points to match the tree", c.Id)
+					}
+				}
+			})
+
+			t.Run("ccomp", func(t *testing.T) {
+				root := find(RetrCComp)
+				count := 0
+				for _, c := range root.Children {
+					count += 1 + len(c.Children)
+					if c.BorderType != Outer || c.ParentId != 1 {
+						t.Errorf("contour %d: expected outer border under the root", c.Id)
+					}
+					for _, h := range c.Children {
+						expected := all[slices.IndexFunc(all, func(e *Contour) bool { return e.Id == h.Id })]
+						if h.BorderType != Hole || h.ParentId != c.Id || expected.ParentId != c.Id || len(h.Children) != 0 {
+							t.Errorf("contour %d: expected hole with no children under its outer border %d", h.Id, c.Id)
+						}
+					}
+				}
+				if count != len(all) {
+					t.Errorf("expected %d contours, got %d", len(all), count)
+				}
+			})
+		})
+	}
+}
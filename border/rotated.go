@@ -0,0 +1,334 @@
+package border
+
+import (
+	"errors"
+	"image"
+	"math"
+)
+
+var (
+	ErrTooFewPoints = errors.New("too few points")
+	ErrNoEllipse    = errors.New("points do not fit an ellipse")
+)
+
+// RotatedRect is a rectangle (or the bounding rectangle of an ellipse) rotated about its centre.
+type RotatedRect struct {
+	Centre PointF
+
+	// Width is the length of the side at Angle, Height the length of the side perpendicular to it.
+	Width  float64
+	Height float64
+
+	// Angle is in degrees from the x axis, clockwise when displayed (ie. y pointing down).
+	Angle float64
+}
+
+// Corners returns the four corners of the rectangle, in the same direction as Outer contours
+// (anti-clockwise when displayed) for non negative Width and Height.
+func (r RotatedRect) Corners() [4]PointF {
+	rad := r.Angle * math.Pi / 180.0
+	ux, uy := math.Cos(rad)*r.Width/2, math.Sin(rad)*r.Width/2
+	vx, vy := -math.Sin(rad)*r.Height/2, math.Cos(rad)*r.Height/2
+
+	return [4]PointF{
+		{r.Centre.X - ux - vx, r.Centre.Y - uy - vy},
+		{r.Centre.X - ux + vx, r.Centre.Y - uy + vy},
+		{r.Centre.X + ux + vx, r.Centre.Y + uy + vy},
+		{r.Centre.X + ux - vx, r.Centre.Y + uy - vy},
+	}
+}
+
+// MinAreaRect returns the smallest (by area) rotated rectangle containing the contour points.
+// One side of the minimum rectangle is always collinear with an edge of the convex hull, so each hull edge is
+// tried in turn using rotating calipers: the hull points furthest along the edge (both ways) and furthest from it
+// only ever move forward around the hull as the edge does, so all the edges are tried in linear time.
+// The Angle is in the range [0, 90).
+func (c *Contour) MinAreaRect() RotatedRect {
+	hull := ConvexHull(c)
+	n := len(hull)
+	switch n {
+	case 0:
+		return RotatedRect{}
+	case 1:
+		return RotatedRect{Centre: PointF{float64(hull[0].X), float64(hull[0].Y)}}
+	}
+
+	// dot returns the (unnormalised) distance along edge e between points i and j of the hull.
+	dot := func(e image.Point, i int, j int) int {
+		d := hull[j%n].Sub(hull[i%n])
+		return d.X*e.X + d.Y*e.Y
+	}
+
+	best := RotatedRect{}
+	bestArea := math.Inf(1)
+
+	// indices of the points furthest along the edge, furthest from it and furthest back along it.
+	maxU, maxV, minU := 1, 1, 1
+	for i := 0; i < n; i++ {
+		a := hull[i]
+		b := hull[(i+1)%n]
+		e := b.Sub(a)
+
+		maxU = max(maxU, i+1)
+		for dot(e, maxU, maxU+1) > 0 {
+			maxU++
+		}
+		maxV = max(maxV, maxU)
+		for abs(cross(a, b, hull[(maxV+1)%n])) > abs(cross(a, b, hull[maxV%n])) {
+			maxV++
+		}
+		minU = max(minU, maxV)
+		for dot(e, minU, minU+1) < 0 {
+			minU++
+		}
+
+		// project the supporting points onto the edge (u) and its perpendicular (v).
+		length := math.Hypot(float64(e.X), float64(e.Y))
+		ux, uy := float64(e.X)/length, float64(e.Y)/length
+		project := func(p image.Point) (float64, float64) {
+			px, py := float64(p.X), float64(p.Y)
+			return px*ux + py*uy, -px*uy + py*ux
+		}
+		_, edgeV := project(a)
+		lowU, _ := project(hull[minU%n])
+		highU, _ := project(hull[maxU%n])
+		_, farV := project(hull[maxV%n])
+		lowV, highV := min(edgeV, farV), max(edgeV, farV)
+
+		area := (highU - lowU) * (highV - lowV)
+		if area < bestArea {
+			bestArea = area
+			cu := (lowU + highU) / 2
+			cv := (lowV + highV) / 2
+			best = RotatedRect{
+				Centre: PointF{cu*ux - cv*uy, cu*uy + cv*ux},
+				Width:  highU - lowU,
+				Height: highV - lowV,
+				Angle:  math.Atan2(uy, ux) * 180.0 / math.Pi,
+			}
+		}
+	}
+
+	return normaliseRectAngle(best)
+}
+
+// normaliseRectAngle rotates the angle into [0, 90), swapping the width and height as required.
+func normaliseRectAngle(r RotatedRect) RotatedRect {
+	r.Angle = math.Mod(r.Angle, 180)
+	if r.Angle < 0 {
+		r.Angle += 180
+	}
+	if r.Angle >= 90 {
+		r.Angle -= 90
+		r.Width, r.Height = r.Height, r.Width
+	}
+	return r
+}
+
+// FitEllipse returns the ellipse that best fits the contour points (least squares), using the direct method
+// of Halir and Flusser. Width is the length of the major axis, Height the minor axis, and Angle (in the
+// range [0, 180)) the direction of the major axis. At least 5 points are required.
+func (c *Contour) FitEllipse() (RotatedRect, error) {
+	n := len(c.Points)
+	if n < 5 {
+		return RotatedRect{}, ErrTooFewPoints
+	}
+
+	// centre the points for numerical stability.
+	mx, my := c.meanPoint()
+
+	// scatter matrices for the quadratic (x², xy, y²) and linear (x, y, 1) parts of the conic.
+	var s1, s2, s3 [3][3]float64
+	for _, p := range c.Points {
+		x := float64(p.X) - mx
+		y := float64(p.Y) - my
+		d1 := [3]float64{x * x, x * y, y * y}
+		d2 := [3]float64{x, y, 1}
+		for i := 0; i < 3; i++ {
+			for j := 0; j < 3; j++ {
+				s1[i][j] += d1[i] * d1[j]
+				s2[i][j] += d1[i] * d2[j]
+				s3[i][j] += d2[i] * d2[j]
+			}
+		}
+	}
+
+	s3Inv, ok := invert3(s3)
+	if !ok {
+		return RotatedRect{}, ErrNoEllipse
+	}
+
+	// t = -inv(s3) * s2ᵀ, which gives the linear coefficients from the quadratic ones.
+	var t [3][3]float64
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				t[i][j] -= s3Inv[i][k] * s2[j][k]
+			}
+		}
+	}
+
+	// reduced scatter matrix m = s1 + s2 * t, premultiplied by the inverse of the constraint matrix.
+	var m [3][3]float64
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			m[i][j] = s1[i][j]
+			for k := 0; k < 3; k++ {
+				m[i][j] += s2[i][k] * t[k][j]
+			}
+		}
+	}
+	m = [3][3]float64{
+		{m[2][0] / 2, m[2][1] / 2, m[2][2] / 2},
+		{-m[1][0], -m[1][1], -m[1][2]},
+		{m[0][0] / 2, m[0][1] / 2, m[0][2] / 2},
+	}
+
+	// the solution is the eigenvector satisfying the ellipse constraint 4ac - b² > 0.
+	var a1 [3]float64
+	found := false
+	for _, lambda := range eigenvalues3(m) {
+		v, ok := eigenvector3(m, lambda)
+		if ok && 4*v[0]*v[2]-v[1]*v[1] > 0 {
+			a1 = v
+			found = true
+			break
+		}
+	}
+	if !found {
+		return RotatedRect{}, ErrNoEllipse
+	}
+
+	var a2 [3]float64
+	for i := 0; i < 3; i++ {
+		for k := 0; k < 3; k++ {
+			a2[i] += t[i][k] * a1[k]
+		}
+	}
+
+	r, ok := conicToEllipse(a1[0], a1[1], a1[2], a2[0], a2[1], a2[2])
+	if !ok {
+		return RotatedRect{}, ErrNoEllipse
+	}
+	r.Centre.X += mx
+	r.Centre.Y += my
+	return r, nil
+}
+
+// conicToEllipse converts the conic Ax² + Bxy + Cy² + Dx + Ey + F = 0 to the centre, axes and angle of an ellipse.
+func conicToEllipse(a float64, b float64, c float64, d float64, e float64, f float64) (RotatedRect, bool) {
+
+	// the conic can be scaled by any value, the formulas below require a (and c) to be positive.
+	if a < 0 {
+		a, b, c, d, e, f = -a, -b, -c, -d, -e, -f
+	}
+
+	den := b*b - 4*a*c
+	if den >= 0 {
+		return RotatedRect{}, false
+	}
+
+	x0 := (2*c*d - b*e) / den
+	y0 := (2*a*e - b*d) / den
+
+	num := 2 * (a*e*e + c*d*d - b*d*e + den*f)
+	root := math.Hypot(a-c, b)
+	major := num * (a + c + root)
+	minor := num * (a + c - root)
+	if major < 0 || minor < 0 {
+		return RotatedRect{}, false
+	}
+
+	angle := math.Atan2(-b, c-a) / 2 * 180.0 / math.Pi
+	angle = math.Mod(angle+180, 180)
+	return RotatedRect{
+		Centre: PointF{x0, y0},
+		Width:  2 * -math.Sqrt(major) / den,
+		Height: 2 * -math.Sqrt(minor) / den,
+		Angle:  angle,
+	}, true
+}
+
+// invert3 inverts a 3x3 matrix, returning false if it is singular.
+func invert3(m [3][3]float64) ([3][3]float64, bool) {
+	var inv [3][3]float64
+	inv[0][0] = m[1][1]*m[2][2] - m[1][2]*m[2][1]
+	inv[0][1] = m[0][2]*m[2][1] - m[0][1]*m[2][2]
+	inv[0][2] = m[0][1]*m[1][2] - m[0][2]*m[1][1]
+	inv[1][0] = m[1][2]*m[2][0] - m[1][0]*m[2][2]
+	inv[1][1] = m[0][0]*m[2][2] - m[0][2]*m[2][0]
+	inv[1][2] = m[0][2]*m[1][0] - m[0][0]*m[1][2]
+	inv[2][0] = m[1][0]*m[2][1] - m[1][1]*m[2][0]
+	inv[2][1] = m[0][1]*m[2][0] - m[0][0]*m[2][1]
+	inv[2][2] = m[0][0]*m[1][1] - m[0][1]*m[1][0]
+
+	det := m[0][0]*inv[0][0] + m[0][1]*inv[1][0] + m[0][2]*inv[2][0]
+	if math.Abs(det) < 1e-12 {
+		return inv, false
+	}
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			inv[i][j] /= det
+		}
+	}
+	return inv, true
+}
+
+// eigenvalues3 returns the real eigenvalues of a 3x3 matrix by solving its characteristic cubic.
+func eigenvalues3(m [3][3]float64) []float64 {
+	// λ³ + bλ² + cλ + d = 0
+	b := -(m[0][0] + m[1][1] + m[2][2])
+	c := m[0][0]*m[1][1] - m[0][1]*m[1][0] + m[0][0]*m[2][2] - m[0][2]*m[2][0] + m[1][1]*m[2][2] - m[1][2]*m[2][1]
+	d := -(m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) - m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) + m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0]))
+
+	// depressed cubic t³ + pt + q = 0 where λ = t - b/3
+	p := c - b*b/3
+	q := 2*b*b*b/27 - b*c/3 + d
+	shift := -b / 3
+
+	disc := q*q/4 + p*p*p/27
+	if disc > 0 {
+		s := math.Sqrt(disc)
+		return []float64{math.Cbrt(-q/2+s) + math.Cbrt(-q/2-s) + shift}
+	}
+
+	if p == 0 {
+		return []float64{shift}
+	}
+
+	r := 2 * math.Sqrt(-p/3)
+	phi := math.Acos(max(-1, min(1, 3*q/(p*r))))
+	roots := make([]float64, 3)
+	for k := 0; k < 3; k++ {
+		roots[k] = r*math.Cos((phi-2*math.Pi*float64(k))/3) + shift
+	}
+	return roots
+}
+
+// eigenvector3 returns the eigenvector of m for the eigenvalue lambda. It is the cross product of two rows
+// of (m - λI), using the pair with the largest result for accuracy.
+func eigenvector3(m [3][3]float64, lambda float64) ([3]float64, bool) {
+	for i := 0; i < 3; i++ {
+		m[i][i] -= lambda
+	}
+
+	crossRows := func(a [3]float64, b [3]float64) [3]float64 {
+		return [3]float64{a[1]*b[2] - a[2]*b[1], a[2]*b[0] - a[0]*b[2], a[0]*b[1] - a[1]*b[0]}
+	}
+
+	best := [3]float64{}
+	bestLen := 0.0
+	for _, pair := range [][2]int{{0, 1}, {0, 2}, {1, 2}} {
+		v := crossRows(m[pair[0]], m[pair[1]])
+		l := v[0]*v[0] + v[1]*v[1] + v[2]*v[2]
+		if l > bestLen {
+			best = v
+			bestLen = l
+		}
+	}
+
+	if bestLen == 0 {
+		return best, false
+	}
+	return best, true
+}
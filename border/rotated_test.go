@@ -0,0 +1,229 @@
+package border
+
+import (
+	"errors"
+	"image"
+	"math"
+	"testing"
+)
+
+// TestMinAreaRect tests the minimum rectangle of axis aligned and rotated shapes.
+func TestMinAreaRect(t *testing.T) {
+	testCases := []struct {
+		name     string
+		points   []image.Point
+		expected RotatedRect
+	}{
+		{
+			name:     "success axis aligned rectangle",
+			points:   []image.Point{{1, 1}, {1, 3}, {5, 3}, {5, 1}},
+			expected: RotatedRect{Centre: PointF{3, 2}, Width: 4, Height: 2, Angle: 0},
+		},
+		{
+			name:     "success rectangle rotated 45 degrees",
+			points:   []image.Point{{0, 2}, {2, 4}, {3, 3}, {1, 1}},
+			expected: RotatedRect{Centre: PointF{1.5, 2.5}, Width: 2 * math.Sqrt2, Height: math.Sqrt2, Angle: 45},
+		},
+		{
+			name:     "success diamond",
+			points:   []image.Point{{2, 0}, {0, 2}, {2, 4}, {4, 2}},
+			expected: RotatedRect{Centre: PointF{2, 2}, Width: 2 * math.Sqrt2, Height: 2 * math.Sqrt2, Angle: 45},
+		},
+		{
+			name:     "success line",
+			points:   []image.Point{{0, 0}, {3, 4}},
+			expected: RotatedRect{Centre: PointF{1.5, 2}, Width: 5, Height: 0, Angle: math.Atan2(4, 3) * 180 / math.Pi},
+		},
+		{
+			name:     "success single point",
+			points:   []image.Point{{7, 8}},
+			expected: RotatedRect{Centre: PointF{7, 8}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := NewContour(2)
+			c.Points = tc.points
+
+			r := c.MinAreaRect()
+			if !rotatedRectsEqual(r, tc.expected, measureTolerance) {
+				t.Errorf("expected %+v, got %+v", tc.expected, r)
+			}
+		})
+	}
+}
+
+// TestMinAreaRectLargeHull tests rotating calipers give the same rectangle as trying every hull point against
+// every hull edge, for hulls with many points.
+func TestMinAreaRectLargeHull(t *testing.T) {
+	testCases := []struct {
+		name    string
+		points  int
+		radiusX float64
+		radiusY float64
+		angle   float64
+		minHull int
+	}{
+		{name: "success 2000-gon", points: 2000, radiusX: 1000000, radiusY: 1000000, minHull: 2000},
+		{name: "success rotated ellipse", points: 2000, radiusX: 1000000, radiusY: 300000, angle: 0.3, minHull: 1900},
+		{name: "success small ellipse", points: 500, radiusX: 200, radiusY: 50, angle: 1.1, minHull: 70},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := NewContour(2)
+			cos, sin := math.Cos(tc.angle), math.Sin(tc.angle)
+			for i := 0; i < tc.points; i++ {
+				theta := 2 * math.Pi * float64(i) / float64(tc.points)
+				x, y := tc.radiusX*math.Cos(theta), tc.radiusY*math.Sin(theta)
+				c.Points = append(c.Points, image.Point{int(math.Round(x*cos - y*sin)), int(math.Round(x*sin + y*cos))})
+			}
+
+			hull := ConvexHull(c)
+			if len(hull) < tc.minHull {
+				t.Fatalf("expected at least %d hull points, got %d", tc.minHull, len(hull))
+			}
+
+			expected := minAreaRectBruteForce(hull)
+			r := c.MinAreaRect()
+			tolerance := measureTolerance * max(1, tc.radiusX)
+			if !rotatedRectsEqual(r, expected, tolerance) {
+				t.Errorf("expected %+v, got %+v", expected, r)
+			}
+		})
+	}
+}
+
+// minAreaRectBruteForce returns the minimum rectangle by projecting every hull point onto every hull edge.
+func minAreaRectBruteForce(hull []image.Point) RotatedRect {
+	best := RotatedRect{}
+	bestArea := math.Inf(1)
+	for i, a := range hull {
+		b := hull[(i+1)%len(hull)]
+		length := math.Hypot(float64(b.X-a.X), float64(b.Y-a.Y))
+		ux, uy := float64(b.X-a.X)/length, float64(b.Y-a.Y)/length
+
+		minU, maxU := math.Inf(1), math.Inf(-1)
+		minV, maxV := math.Inf(1), math.Inf(-1)
+		for _, p := range hull {
+			pu := float64(p.X)*ux + float64(p.Y)*uy
+			pv := -float64(p.X)*uy + float64(p.Y)*ux
+			minU, maxU = min(minU, pu), max(maxU, pu)
+			minV, maxV = min(minV, pv), max(maxV, pv)
+		}
+
+		if area := (maxU - minU) * (maxV - minV); area < bestArea {
+			bestArea = area
+			cu, cv := (minU+maxU)/2, (minV+maxV)/2
+			best = RotatedRect{
+				Centre: PointF{cu*ux - cv*uy, cu*uy + cv*ux},
+				Width:  maxU - minU,
+				Height: maxV - minV,
+				Angle:  math.Atan2(uy, ux) * 180.0 / math.Pi,
+			}
+		}
+	}
+	return normaliseRectAngle(best)
+}
+
+// TestFitEllipse tests fitting ellipses to points on the boundary of known ellipses.
+func TestFitEllipse(t *testing.T) {
+	testCases := []struct {
+		name      string
+		points    []image.Point
+		expected  RotatedRect
+		tolerance float64
+		expectErr error
+	}{
+		{
+			name:      "success axis aligned",
+			points:    ellipsePoints(RotatedRect{Centre: PointF{100, 80}, Width: 200, Height: 100}, 72),
+			expected:  RotatedRect{Centre: PointF{100, 80}, Width: 200, Height: 100},
+			tolerance: 0.5,
+		},
+		{
+			name:      "success rotated",
+			points:    ellipsePoints(RotatedRect{Centre: PointF{300, 250}, Width: 400, Height: 150, Angle: 30}, 90),
+			expected:  RotatedRect{Centre: PointF{300, 250}, Width: 400, Height: 150, Angle: 30},
+			tolerance: 0.5,
+		},
+		{
+			name:      "success rotated past 90 degrees",
+			points:    ellipsePoints(RotatedRect{Centre: PointF{300, 250}, Width: 300, Height: 200, Angle: 120}, 90),
+			expected:  RotatedRect{Centre: PointF{300, 250}, Width: 300, Height: 200, Angle: 120},
+			tolerance: 0.5,
+		},
+		{
+			name:      "error with too few points",
+			points:    []image.Point{{0, 0}, {1, 0}, {1, 1}, {0, 1}},
+			expectErr: ErrTooFewPoints,
+		},
+		{
+			name:      "error with collinear points",
+			points:    []image.Point{{0, 0}, {1, 1}, {2, 2}, {3, 3}, {4, 4}, {5, 5}},
+			expectErr: ErrNoEllipse,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := NewContour(2)
+			c.Points = tc.points
+
+			r, err := c.FitEllipse()
+			if tc.expectErr != nil {
+				if !errors.Is(err, tc.expectErr) {
+					t.Errorf("expected error %v, got %v", tc.expectErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if !rotatedRectsEqual(r, tc.expected, tc.tolerance) {
+				t.Errorf("expected %+v, got %+v", tc.expected, r)
+			}
+		})
+	}
+}
+
+// TestRotatedRectCorners tests the corners are generated anti-clockwise around the centre.
+func TestRotatedRectCorners(t *testing.T) {
+	r := RotatedRect{Centre: PointF{3, 2}, Width: 4, Height: 2, Angle: 90}
+	expected := [4]PointF{{4, 0}, {2, 0}, {2, 4}, {4, 4}}
+
+	corners := r.Corners()
+	for i := range expected {
+		if math.Abs(corners[i].X-expected[i].X) > measureTolerance || math.Abs(corners[i].Y-expected[i].Y) > measureTolerance {
+			t.Fatalf("expected corners %v, got %v", expected, corners)
+		}
+	}
+}
+
+// ellipsePoints generates count points (rounded to the nearest pixel) on the boundary of the ellipse.
+func ellipsePoints(e RotatedRect, count int) []image.Point {
+	rad := e.Angle * math.Pi / 180
+	points := []image.Point{}
+	for i := 0; i < count; i++ {
+		theta := 2 * math.Pi * float64(i) / float64(count)
+		x := e.Width / 2 * math.Cos(theta)
+		y := e.Height / 2 * math.Sin(theta)
+		points = append(points, image.Point{
+			X: int(math.Round(e.Centre.X + x*math.Cos(rad) - y*math.Sin(rad))),
+			Y: int(math.Round(e.Centre.Y + x*math.Sin(rad) + y*math.Cos(rad))),
+		})
+	}
+	return points
+}
+
+// rotatedRectsEqual compares rectangles, with the angle compared in degrees.
+func rotatedRectsEqual(a RotatedRect, b RotatedRect, tolerance float64) bool {
+	return math.Abs(a.Centre.X-b.Centre.X) <= tolerance &&
+		math.Abs(a.Centre.Y-b.Centre.Y) <= tolerance &&
+		math.Abs(a.Width-b.Width) <= tolerance &&
+		math.Abs(a.Height-b.Height) <= tolerance &&
+		math.Abs(a.Angle-b.Angle) <= tolerance
+}
@@ -0,0 +1,139 @@
+package border
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/kpfaulkner/borders/common"
+)
+
+const (
+	defaultTileSize = 512
+)
+
+// TileSource supplies an image a region at a time, for images too large to decode in one go.
+type TileSource interface {
+
+	// Bounds returns the bounds of the full image.
+	Bounds() image.Rectangle
+
+	// ReadTile returns the pixels for region r of the image. r is always within Bounds.
+	ReadTile(r image.Rectangle) (image.Image, error)
+}
+
+// TiledOptions controls FindContoursTiled.
+type TiledOptions struct {
+
+	// TileSize is the width and height of the tiles read from the source. Defaults to 512.
+	TileSize int
+
+	// MaxTiles is the maximum number of tiles held in memory at any time.
+	// Defaults to two rows of tiles across the image, plus a few spare for contours that cross rows.
+	MaxTiles int
+
+	// SpillDir is where tiles modified during border detection are written when evicted from memory.
+	// Defaults to os.TempDir().
+	SpillDir string
+
+	// Classifier decides which pixels are foreground. If nil, NewNonBlackClassifier is used.
+	// Note: the classifier is applied to each tile separately, so classifiers that inspect the image
+	// (eg. NewOtsuClassifier) will only see a single tile.
+	Classifier Classifier
+}
+
+// imageTileSource is a TileSource for an image already in memory.
+type imageTileSource struct {
+	img image.Image
+}
+
+// NewImageTileSource wraps an image.Image as a TileSource.
+func NewImageTileSource(img image.Image) TileSource {
+	return &imageTileSource{img: img}
+}
+
+func (s *imageTileSource) Bounds() image.Rectangle {
+	return s.img.Bounds()
+}
+
+func (s *imageTileSource) ReadTile(r image.Rectangle) (image.Image, error) {
+	return &subImage{img: s.img, r: r}, nil
+}
+
+// subImage restricts the bounds of an image without requiring the image to implement SubImage.
+type subImage struct {
+	img image.Image
+	r   image.Rectangle
+}
+
+func (s *subImage) ColorModel() color.Model {
+	return s.img.ColorModel()
+}
+
+func (s *subImage) Bounds() image.Rectangle {
+	return s.r
+}
+
+func (s *subImage) At(x int, y int) color.Color {
+	return s.img.At(x, y)
+}
+
+// FindContoursTiled finds the contours of an image supplied one tile at a time, so memory is bounded by the
+// tile size and number of tiles held in memory rather than the size of the image.
+//
+// Border following can wander anywhere in the image, so rather than stitching the contours of individual tiles
+// the tiles are loaded on demand (see common.NewPagedSuzukiImage) and FindContours runs as normal. This means
+// the result is identical to loading the full image with LoadFromImage (no erode/dilate) and calling FindContours.
+func FindContoursTiled(src TileSource, opts TiledOptions) (*Contour, error) {
+
+	tileSize := opts.TileSize
+	if tileSize <= 0 {
+		tileSize = defaultTileSize
+	}
+
+	bounds := src.Bounds()
+	maxTiles := opts.MaxTiles
+	if maxTiles <= 0 {
+		maxTiles = 2*((bounds.Dx()+2+tileSize-1)/tileSize) + 8
+	}
+
+	classifier := opts.Classifier
+	if classifier == nil {
+		classifier = NewNonBlackClassifier()
+	}
+
+	loader := func(r image.Rectangle, dst []int32) error {
+		tile, err := src.ReadTile(r.Add(bounds.Min))
+		if err != nil {
+			return err
+		}
+
+		isForeground := classifier(tile)
+		colourAt := pixelColours(tile)
+		tileBounds := tile.Bounds()
+		for y := 0; y < r.Dy(); y++ {
+			for x := 0; x < r.Dx(); x++ {
+				if isForeground(colourAt(tileBounds.Min.X+x, tileBounds.Min.Y+y)) {
+					dst[y*r.Dx()+x] = 1
+				}
+			}
+		}
+		return nil
+	}
+
+	si, err := common.NewPagedSuzukiImage(bounds.Dx(), bounds.Dy(), tileSize, maxTiles, opts.SpillDir, loader)
+	if err != nil {
+		return nil, err
+	}
+	defer si.Close()
+
+	contour, err := FindContours(si)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := si.Err(); err != nil {
+		return nil, err
+	}
+
+	return contour, nil
+}
@@ -0,0 +1,113 @@
+package border
+
+import (
+	"errors"
+	"image"
+	"os"
+	"slices"
+	"testing"
+)
+
+// TestFindContoursTiled checks tiled contour detection matches detection over the full image, including
+// when the number of tiles in memory is small enough to force tiles to be spilled to disk.
+func TestFindContoursTiled(t *testing.T) {
+	testCases := []struct {
+		name     string
+		filename string
+		opts     TiledOptions
+	}{
+		{
+			name:     "success with default options",
+			filename: `../testimages/florida.png`,
+		},
+		{
+			name:     "success with small tiles",
+			filename: `../testimages/unittest1.png`,
+			opts:     TiledOptions{TileSize: 7},
+		},
+		{
+			name:     "success with spilled tiles",
+			filename: `../testimages/florida.png`,
+			opts:     TiledOptions{TileSize: 37, MaxTiles: 3, SpillDir: t.TempDir()},
+		},
+		{
+			name:     "success with single tile in memory",
+			filename: `../testimages/image1.png`,
+			opts:     TiledOptions{TileSize: 16, MaxTiles: 1, SpillDir: t.TempDir()},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			img := decodeTestImage(t, tc.filename)
+
+			si, err := LoadFromImage(img, LoadOptions{})
+			if err != nil {
+				t.Fatalf("Unable to load test image: %s", err.Error())
+			}
+			expected, err := FindContours(si)
+			if err != nil {
+				t.Fatalf("Unable to find contours: %s", err.Error())
+			}
+
+			cont, err := FindContoursTiled(NewImageTileSource(img), tc.opts)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if !slices.Equal(cont.GetAllPoints(), expected.GetAllPoints()) {
+				t.Errorf("expected tiled contour points to match full image contour points")
+			}
+			if !slices.Equal(contourIds(cont), contourIds(expected)) {
+				t.Errorf("expected tiled contour ids to match full image contour ids")
+			}
+
+			if tc.opts.SpillDir != "" {
+				entries, _ := os.ReadDir(tc.opts.SpillDir)
+				if len(entries) != 0 {
+					t.Errorf("expected spill file to be removed, found %d files", len(entries))
+				}
+			}
+		})
+	}
+}
+
+// TestFindContoursTiledError checks errors reading tiles are returned.
+func TestFindContoursTiledError(t *testing.T) {
+	src := &failingTileSource{bounds: image.Rect(0, 0, 100, 100)}
+	_, err := FindContoursTiled(src, TiledOptions{TileSize: 10})
+	if !errors.Is(err, errTileRead) {
+		t.Errorf("expected tile read error, got %v", err)
+	}
+}
+
+var errTileRead = errors.New("unable to read tile")
+
+// failingTileSource fails to read any tile.
+type failingTileSource struct {
+	bounds image.Rectangle
+}
+
+func (s *failingTileSource) Bounds() image.Rectangle {
+	return s.bounds
+}
+
+func (s *failingTileSource) ReadTile(r image.Rectangle) (image.Image, error) {
+	return nil, errTileRead
+}
+
+// decodeTestImage decodes an image file for test purposes.
+func decodeTestImage(t *testing.T, filename string) image.Image {
+	t.Helper()
+	f, err := os.Open(filename)
+	if err != nil {
+		t.Fatalf("Unable to open test image: %s", err.Error())
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		t.Fatalf("Unable to decode test image: %s", err.Error())
+	}
+	return img
+}
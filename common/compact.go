@@ -0,0 +1,65 @@
+package common
+
+// compactStore holds 2 bits per pixel. One bit indicates if the pixel is populated, the other
+// indicates the pixel has a label (any value other than 0 or 1) which is then held in a map.
+// During border detection only border pixels get labels, so the map stays small compared to the image.
+type compactStore struct {
+	populated []uint64
+	labelled  []uint64
+	labels    map[int]int32
+}
+
+func newCompactStore(size int) *compactStore {
+	words := (size + 63) / 64
+	return &compactStore{
+		populated: make([]uint64, words),
+		labelled:  make([]uint64, words),
+		labels:    make(map[int]int32),
+	}
+}
+
+func (cs *compactStore) get(idx int) int {
+	word, bit := idx>>6, uint64(1)<<(idx&63)
+	if cs.populated[word]&bit == 0 {
+		return 0
+	}
+	if cs.labelled[word]&bit == 0 {
+		return 1
+	}
+	return int(cs.labels[idx])
+}
+
+func (cs *compactStore) set(idx int, val int) {
+	word, bit := idx>>6, uint64(1)<<(idx&63)
+
+	// clear any existing label.
+	if cs.labelled[word]&bit != 0 {
+		cs.labelled[word] &^= bit
+		delete(cs.labels, idx)
+	}
+
+	switch val {
+	case 0:
+		cs.populated[word] &^= bit
+	case 1:
+		cs.populated[word] |= bit
+	default:
+		cs.populated[word] |= bit
+		cs.labelled[word] |= bit
+		cs.labels[idx] = int32(val)
+	}
+}
+
+func (cs *compactStore) clone() *compactStore {
+	c := &compactStore{
+		populated: make([]uint64, len(cs.populated)),
+		labelled:  make([]uint64, len(cs.labelled)),
+		labels:    make(map[int]int32, len(cs.labels)),
+	}
+	copy(c.populated, cs.populated)
+	copy(c.labelled, cs.labelled)
+	for k, v := range cs.labels {
+		c.labels[k] = v
+	}
+	return c
+}
@@ -0,0 +1,14 @@
+package common
+
+// Connectivity determines which neighbouring foreground pixels are treated as connected.
+// The background always uses the other neighbourhood (as per Suzuki and Abe), so 8 connected foreground
+// has 4 connected background and vice versa.
+type Connectivity int
+
+const (
+	// Connectivity8 connects pixels that share an edge or a corner. This is the default.
+	Connectivity8 Connectivity = iota
+
+	// Connectivity4 only connects pixels that share an edge, so pixels touching diagonally are separate.
+	Connectivity4
+)
@@ -1,4 +1,6 @@
 // Package common has the SuzukiImage structure definition.
 //
 // SuzukiImage is an intermediate representation of the input image which is easier for border detection.
+// The pixels can be held densely (an int per pixel) or compactly (2 bits per pixel plus a sparse map of
+// border labels) for images that would otherwise not fit in memory.
 package common
@@ -0,0 +1,217 @@
+package common
+
+import (
+	"container/list"
+	"encoding/binary"
+	"errors"
+	"image"
+	"os"
+)
+
+// TileLoader fills dst with the pixels (0 or 1) of the rectangle r of the source image.
+// dst holds r.Dx()*r.Dy() values in row order.
+type TileLoader func(r image.Rectangle, dst []int32) error
+
+// pagedTile is a single tile of a paged image that is currently held in memory.
+type pagedTile struct {
+	idx   int
+	data  []int32
+	dirty bool
+}
+
+// pagedStore only holds a limited number of tiles in memory. Tiles are loaded from the TileLoader when
+// first used and modified tiles are written to a temporary spill file when evicted, so memory usage is
+// bounded by the tile size and number of tiles rather than the image size.
+type pagedStore struct {
+	width    int
+	tileSize int
+	tilesX   int
+	maxTiles int
+	bounds   image.Rectangle // source image bounds, in padded co-ords.
+	loader   TileLoader
+
+	resident map[int]*list.Element
+	lru      *list.List
+	spilled  []bool
+	spill    *os.File
+	spillDir string
+	buf      []byte
+
+	// last tile accessed, saves the map lookup for the common case.
+	last *pagedTile
+
+	err error
+}
+
+// NewPagedSuzukiImage creates a padded SuzukiImage of the given (unpadded) dimensions which loads its pixels
+// a tile at a time using loader. At most maxTiles tiles of tileSize x tileSize pixels are held in memory,
+// any others that have been modified are kept in a temporary file in spillDir (os.TempDir() if empty).
+//
+// Close must be called once the image is no longer required to remove the temporary file. Any error
+// encountered while loading or spilling tiles is returned by Err.
+func NewPagedSuzukiImage(width int, height int, tileSize int, maxTiles int, spillDir string, loader TileLoader) (*SuzukiImage, error) {
+	if tileSize <= 0 || maxTiles <= 0 {
+		return nil, errors.New("tile size and max tiles must be greater than 0")
+	}
+	if loader == nil {
+		return nil, errors.New("tile loader must not be nil")
+	}
+
+	si := SuzukiImage{}
+	si.Width = width + 2
+	si.Height = height + 2
+	si.dataLen = si.Width * si.Height
+	si.hasPadding = true
+	si.storage = PagedStorage
+
+	tilesX := (si.Width + tileSize - 1) / tileSize
+	tilesY := (si.Height + tileSize - 1) / tileSize
+	si.store = &pagedStore{
+		width:    si.Width,
+		tileSize: tileSize,
+		tilesX:   tilesX,
+		maxTiles: maxTiles,
+		bounds:   image.Rect(1, 1, width+1, height+1),
+		loader:   loader,
+		resident: make(map[int]*list.Element),
+		lru:      list.New(),
+		spilled:  make([]bool, tilesX*tilesY),
+		spillDir: spillDir,
+	}
+	return &si, nil
+}
+
+func (ps *pagedStore) get(idx int) int {
+	t, offset := ps.locate(idx)
+	return int(t.data[offset])
+}
+
+func (ps *pagedStore) set(idx int, val int) {
+	t, offset := ps.locate(idx)
+	t.data[offset] = int32(val)
+	t.dirty = true
+}
+
+// locate returns the tile holding idx and the offset of idx within the tile.
+func (ps *pagedStore) locate(idx int) (*pagedTile, int) {
+	x := idx % ps.width
+	y := idx / ps.width
+	tileIdx := (y/ps.tileSize)*ps.tilesX + x/ps.tileSize
+	offset := (y%ps.tileSize)*ps.tileSize + x%ps.tileSize
+
+	if ps.last != nil && ps.last.idx == tileIdx {
+		return ps.last, offset
+	}
+
+	if e, ok := ps.resident[tileIdx]; ok {
+		ps.lru.MoveToFront(e)
+		ps.last = e.Value.(*pagedTile)
+		return ps.last, offset
+	}
+
+	ps.last = ps.load(tileIdx)
+	return ps.last, offset
+}
+
+// load makes a tile resident, evicting the least recently used tile if required.
+func (ps *pagedStore) load(tileIdx int) *pagedTile {
+	var t *pagedTile
+	if ps.lru.Len() >= ps.maxTiles {
+		e := ps.lru.Back()
+		t = e.Value.(*pagedTile)
+		ps.lru.Remove(e)
+		delete(ps.resident, t.idx)
+		if t.dirty {
+			ps.recordErr(ps.writeSpill(t))
+		}
+		clear(t.data)
+	} else {
+		t = &pagedTile{data: make([]int32, ps.tileSize*ps.tileSize)}
+	}
+
+	t.idx = tileIdx
+	t.dirty = false
+	if ps.spilled[tileIdx] {
+		ps.recordErr(ps.readSpill(t))
+	} else {
+		ps.recordErr(ps.readSource(t))
+	}
+
+	ps.resident[tileIdx] = ps.lru.PushFront(t)
+	return t
+}
+
+// readSource fills the tile from the loader. Any part of the tile outside the source image (padding or
+// beyond the right/bottom edge) is left as 0.
+func (ps *pagedStore) readSource(t *pagedTile) error {
+	tileX := (t.idx % ps.tilesX) * ps.tileSize
+	tileY := (t.idx / ps.tilesX) * ps.tileSize
+	r := image.Rect(tileX, tileY, tileX+ps.tileSize, tileY+ps.tileSize).Intersect(ps.bounds)
+	if r.Empty() {
+		return nil
+	}
+
+	dst := make([]int32, r.Dx()*r.Dy())
+	err := ps.loader(r.Sub(image.Point{1, 1}), dst)
+	if err != nil {
+		return err
+	}
+
+	for y := 0; y < r.Dy(); y++ {
+		copy(t.data[(r.Min.Y-tileY+y)*ps.tileSize+r.Min.X-tileX:], dst[y*r.Dx():(y+1)*r.Dx()])
+	}
+	return nil
+}
+
+// writeSpill writes a modified tile to the spill file.
+func (ps *pagedStore) writeSpill(t *pagedTile) error {
+	if ps.spill == nil {
+		f, err := os.CreateTemp(ps.spillDir, "borders-tiles-*")
+		if err != nil {
+			return err
+		}
+		ps.spill = f
+		ps.buf = make([]byte, len(t.data)*4)
+	}
+
+	for i, v := range t.data {
+		binary.LittleEndian.PutUint32(ps.buf[i*4:], uint32(v))
+	}
+	_, err := ps.spill.WriteAt(ps.buf, int64(t.idx)*int64(len(ps.buf)))
+	if err != nil {
+		return err
+	}
+	ps.spilled[t.idx] = true
+	return nil
+}
+
+// readSpill reads a previously modified tile from the spill file.
+func (ps *pagedStore) readSpill(t *pagedTile) error {
+	_, err := ps.spill.ReadAt(ps.buf, int64(t.idx)*int64(len(ps.buf)))
+	if err != nil {
+		return err
+	}
+
+	for i := range t.data {
+		t.data[i] = int32(binary.LittleEndian.Uint32(ps.buf[i*4:]))
+	}
+	return nil
+}
+
+// recordErr keeps the first error encountered.
+func (ps *pagedStore) recordErr(err error) {
+	if err != nil && ps.err == nil {
+		ps.err = err
+	}
+}
+
+// close removes the spill file.
+func (ps *pagedStore) close() error {
+	if ps.spill == nil {
+		return nil
+	}
+	name := ps.spill.Name()
+	ps.spill.Close()
+	ps.spill = nil
+	return os.Remove(name)
+}
@@ -0,0 +1,82 @@
+package common
+
+import (
+	"image"
+	"testing"
+)
+
+// TestPagedStorage checks values written to a paged image survive tiles being evicted and reloaded.
+func TestPagedStorage(t *testing.T) {
+
+	// source has a single populated pixel at 2,3
+	loader := func(r image.Rectangle, dst []int32) error {
+		if (image.Point{2, 3}).In(r) {
+			dst[(3-r.Min.Y)*r.Dx()+2-r.Min.X] = 1
+		}
+		return nil
+	}
+
+	si, err := NewPagedSuzukiImage(10, 8, 3, 1, t.TempDir(), loader)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer si.Close()
+
+	if si.Width != 12 || si.Height != 10 || !si.HasPadding() || si.Storage() != PagedStorage {
+		t.Fatalf("expected padded 12x10 paged image")
+	}
+
+	// padding shifts the source pixel by 1,1
+	if si.GetXY(3, 4) != 1 || si.GetXY(2, 3) != 0 {
+		t.Errorf("expected source pixel to be loaded with padding offset")
+	}
+
+	// write to every pixel, forcing each tile to be spilled as only 1 tile is held in memory.
+	for y := 0; y < si.Height; y++ {
+		for x := 0; x < si.Width; x++ {
+			si.SetXY(x, y, -(y*si.Width + x))
+		}
+	}
+
+	for y := 0; y < si.Height; y++ {
+		for x := 0; x < si.Width; x++ {
+			if si.GetXY(x, y) != -(y*si.Width + x) {
+				t.Fatalf("expected %d at %d,%d, got %d", -(y*si.Width + x), x, y, si.GetXY(x, y))
+			}
+		}
+	}
+
+	if si.Err() != nil {
+		t.Errorf("expected no error, got %v", si.Err())
+	}
+}
+
+// TestNewPagedSuzukiImageErrors checks invalid parameters are rejected rather than failing on first use.
+func TestNewPagedSuzukiImageErrors(t *testing.T) {
+	loader := func(r image.Rectangle, dst []int32) error {
+		return nil
+	}
+
+	testCases := []struct {
+		name     string
+		tileSize int
+		maxTiles int
+		loader   TileLoader
+	}{
+		{name: "error with zero tile size", tileSize: 0, maxTiles: 1, loader: loader},
+		{name: "error with zero max tiles", tileSize: 3, maxTiles: 0, loader: loader},
+		{name: "error with nil loader", tileSize: 3, maxTiles: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			si, err := NewPagedSuzukiImage(10, 8, tc.tileSize, tc.maxTiles, t.TempDir(), tc.loader)
+			if err == nil {
+				t.Errorf("expected error, got nil")
+			}
+			if si != nil {
+				t.Errorf("expected no image, got %v", si)
+			}
+		})
+	}
+}
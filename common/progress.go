@@ -0,0 +1,23 @@
+package common
+
+// Progress reports how far through an image (or its contours) a long running operation is.
+type Progress struct {
+
+	// RowsScanned is the number of rows of the image processed so far.
+	RowsScanned int
+
+	// TotalRows is the number of rows in the image.
+	TotalRows int
+
+	// ContoursFound is the number of contours found so far (0 for operations that don't generate contours).
+	ContoursFound int
+
+	// ContoursConverted is the number of contours converted so far, of TotalContours, by operations that
+	// convert contours rather than scan an image.
+	ContoursConverted int
+	TotalContours     int
+}
+
+// ProgressFunc is called periodically by long running operations. It is called from the goroutine
+// running the operation, so should return quickly.
+type ProgressFunc func(p Progress)
@@ -6,6 +6,23 @@ import (
 	"strings"
 )
 
+// Storage determines how the pixels of a SuzukiImage are held in memory.
+type Storage int
+
+const (
+
+	// DenseStorage holds every pixel as an int. Fastest option but costs 8 bytes per pixel.
+	DenseStorage Storage = iota
+
+	// CompactStorage holds 2 bits per pixel, with the border labels written by FindContours kept
+	// in a sparse map. Slower than DenseStorage but allows very large images to fit in memory.
+	CompactStorage
+
+	// PagedStorage only holds a limited number of tiles of the image in memory, loading them on demand.
+	// Only available via NewPagedSuzukiImage.
+	PagedStorage
+)
+
 // SuzukiImage is the basic structure we use to define an image when trying to find contours.
 type SuzukiImage struct {
 	Width   int
@@ -16,10 +33,26 @@ type SuzukiImage struct {
 	// Indicates if a 1 pixel padding has been applied to around the image.
 	// This helps with imagery where it goes RIGHT up to the edge.
 	hasPadding bool
+
+	// store is used instead of data for anything other than DenseStorage
+	storage Storage
+	store   pixelStore
+}
+
+// pixelStore is the backing store for non dense storage.
+type pixelStore interface {
+	get(idx int) int
+	set(idx int, val int)
 }
 
 // NewSuzukiImage creates a new SuzukiImage of specific dimensions.
 func NewSuzukiImage(width int, height int, hasPadding bool) *SuzukiImage {
+	return NewSuzukiImageWithStorage(width, height, hasPadding, DenseStorage)
+}
+
+// NewSuzukiImageWithStorage creates a new SuzukiImage of specific dimensions using the requested storage.
+// PagedStorage requires a source for the tiles so falls back to DenseStorage, see NewPagedSuzukiImage.
+func NewSuzukiImageWithStorage(width int, height int, hasPadding bool, storage Storage) *SuzukiImage {
 	si := SuzukiImage{}
 	padding := 0
 	if hasPadding {
@@ -27,9 +60,16 @@ func NewSuzukiImage(width int, height int, hasPadding bool) *SuzukiImage {
 	}
 	si.Width = width + padding
 	si.Height = height + padding
-	si.data = make([]int, si.Width*si.Height)
 	si.dataLen = si.Width * si.Height // just saves us calculating a lot
 	si.hasPadding = hasPadding
+	switch storage {
+	case CompactStorage:
+		si.storage = CompactStorage
+		si.store = newCompactStore(si.dataLen)
+	default:
+		si.storage = DenseStorage
+		si.data = make([]int, si.dataLen)
+	}
 	return &si
 }
 
@@ -40,35 +80,104 @@ func NewSuzukiImageFromData(width int, height int, hasPadding bool, data []int)
 	return si
 }
 
-// Get returns the value of a given point
+// GetAllData returns the value of every pixel.
+// For anything other than DenseStorage this allocates a new slice holding every pixel.
 func (si *SuzukiImage) GetAllData() []int {
-	return si.data
+	if si.store == nil {
+		return si.data
+	}
+
+	data := make([]int, si.dataLen)
+	for i := range data {
+		data[i] = si.store.get(i)
+	}
+	return data
 }
 
 // Get returns the value of a given point
 func (si *SuzukiImage) Get(p image.Point) int {
 	idx := p.Y*si.Width + p.X
+	if si.store != nil {
+		return si.store.get(idx)
+	}
 	return si.data[idx]
 }
 
 // GetXY returns the value of a given x/y
 func (si *SuzukiImage) GetXY(x int, y int) int {
 	idx := y*si.Width + x
+	if si.store != nil {
+		return si.store.get(idx)
+	}
 	return si.data[idx]
 }
 
 // Set sets the value at a given point
 func (si *SuzukiImage) Set(p image.Point, val int) {
 	idx := p.Y*si.Width + p.X
+	if si.store != nil {
+		si.store.set(idx, val)
+		return
+	}
 	si.data[idx] = val
 }
 
 // SetXY sets the value at a given x/y
 func (si *SuzukiImage) SetXY(x int, y int, val int) {
 	idx := y*si.Width + x
+	if si.store != nil {
+		si.store.set(idx, val)
+		return
+	}
 	si.data[idx] = val
 }
 
+// Clone returns a copy of the image. PagedStorage images are copied into DenseStorage.
+func (si *SuzukiImage) Clone() *SuzukiImage {
+	c := &SuzukiImage{
+		Width:      si.Width,
+		Height:     si.Height,
+		dataLen:    si.dataLen,
+		hasPadding: si.hasPadding,
+		storage:    si.storage,
+	}
+
+	switch store := si.store.(type) {
+	case nil:
+		c.data = make([]int, len(si.data))
+		copy(c.data, si.data)
+	case *compactStore:
+		c.store = store.clone()
+	default:
+		c.storage = DenseStorage
+		c.data = si.GetAllData()
+	}
+	return c
+}
+
+// Storage returns the type of storage used by the image.
+func (si *SuzukiImage) Storage() Storage {
+	return si.storage
+}
+
+// Err returns the first error encountered while loading or storing pixels.
+// Only PagedStorage can encounter errors, as tiles are read on demand.
+func (si *SuzukiImage) Err() error {
+	if ps, ok := si.store.(*pagedStore); ok {
+		return ps.err
+	}
+	return nil
+}
+
+// Close releases any temporary resources (eg. files) used by the image.
+// Only required for PagedStorage.
+func (si *SuzukiImage) Close() error {
+	if ps, ok := si.store.(*pagedStore); ok {
+		return ps.close()
+	}
+	return nil
+}
+
 func (si *SuzukiImage) HasPadding() bool {
 	return si.hasPadding
 }
@@ -77,10 +186,9 @@ func (si *SuzukiImage) HasPadding() bool {
 func (si *SuzukiImage) DisplayAsText() []string {
 	s := []string{}
 	for y := 0; y < si.Height; y++ {
-		ss := si.data[y*si.Width : (y*si.Width + si.Width)]
 		t := []string{}
-		for _, i := range ss {
-			t = append(t, fmt.Sprintf("%d", i))
+		for x := 0; x < si.Width; x++ {
+			t = append(t, fmt.Sprintf("%d", si.GetXY(x, y)))
 		}
 		s = append(s, strings.Join(t, " ")+"\n")
 	}
@@ -94,9 +202,20 @@ func (si *SuzukiImage) Equals(other *SuzukiImage) bool {
 		return false
 	}
 
-	for i := 0; i < si.dataLen; i++ {
-		if si.data[i] != other.data[i] {
-			return false
+	if si.store == nil && other.store == nil {
+		for i := 0; i < si.dataLen; i++ {
+			if si.data[i] != other.data[i] {
+				return false
+			}
+		}
+		return true
+	}
+
+	for y := 0; y < si.Height; y++ {
+		for x := 0; x < si.Width; x++ {
+			if si.GetXY(x, y) != other.GetXY(x, y) {
+				return false
+			}
 		}
 	}
 	return true
@@ -56,3 +56,93 @@ func TestSuzuki(t *testing.T) {
 	}
 
 }
+
+// TestCompactStorage checks compact storage returns the same values as dense storage.
+func TestCompactStorage(t *testing.T) {
+
+	dense := NewSuzukiImage(70, 3, true)
+	compact := NewSuzukiImageWithStorage(70, 3, true, CompactStorage)
+	if compact.Width != dense.Width || compact.Height != dense.Height || compact.Storage() != CompactStorage {
+		t.Fatalf("expected compact image to match dense dimensions")
+	}
+
+	values := []int{1, 0, -5, 7, 1, 123456, 0, -1}
+	for i, v := range values {
+		p := image.Point{X: (i * 13) % dense.Width, Y: i % dense.Height}
+		dense.Set(p, v)
+		compact.Set(p, v)
+		if compact.Get(p) != v {
+			t.Errorf("expected value %d at %v, got %d", v, p, compact.Get(p))
+		}
+	}
+
+	// overwrite a label with a populated pixel and a populated pixel with nothing.
+	dense.SetXY(13, 1, 1)
+	compact.SetXY(13, 1, 1)
+	dense.SetXY(0, 0, 0)
+	compact.SetXY(0, 0, 0)
+
+	if !compact.Equals(dense) || !dense.Equals(compact) {
+		t.Errorf("expected compact and dense images to be equal")
+	}
+
+	if slices.Compare(compact.GetAllData(), dense.GetAllData()) != 0 {
+		t.Errorf("expected compact and dense data to be equal")
+	}
+
+	if slices.Compare(compact.DisplayAsText(), dense.DisplayAsText()) != 0 {
+		t.Errorf("expected compact and dense text to be equal")
+	}
+}
+
+func BenchmarkDenseStorage(b *testing.B) {
+	benchmarkStorage(b, DenseStorage)
+}
+
+func BenchmarkCompactStorage(b *testing.B) {
+	benchmarkStorage(b, CompactStorage)
+}
+
+// benchmarkStorage sets and reads every pixel of a 1000x1000 image, labelling 1 in 100 pixels
+// in a similar manner to border detection.
+func benchmarkStorage(b *testing.B, storage Storage) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		si := NewSuzukiImageWithStorage(1000, 1000, false, storage)
+		for y := 0; y < si.Height; y++ {
+			for x := 0; x < si.Width; x++ {
+				val := (x + y) % 2
+				if x%100 == 0 {
+					val = -(x + y)
+				}
+				si.SetXY(x, y, val)
+			}
+		}
+
+		total := 0
+		for y := 0; y < si.Height; y++ {
+			for x := 0; x < si.Width; x++ {
+				total += si.GetXY(x, y)
+			}
+		}
+	}
+}
+
+// TestClone checks cloned images are equal but independent of the original.
+func TestClone(t *testing.T) {
+	for _, storage := range []Storage{DenseStorage, CompactStorage} {
+		si := NewSuzukiImageWithStorage(4, 4, true, storage)
+		si.SetXY(1, 1, 1)
+		si.SetXY(2, 2, -7)
+
+		c := si.Clone()
+		if !c.Equals(si) || c.HasPadding() != si.HasPadding() || c.Storage() != storage {
+			t.Errorf("expected clone to equal original for storage %d", storage)
+		}
+
+		c.SetXY(1, 1, 0)
+		if si.GetXY(1, 1) != 1 {
+			t.Errorf("expected original to be unmodified for storage %d", storage)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package converters
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrWorldFileNotFound = errors.New("no world file found for image")
+)
+
+// NewAffineConverter returns a function that applies a six parameter affine transform to a point.
+//
+//	X = a*x + b*y + c
+//	Y = d*x + e*y + f
+//
+// The parameters use the same naming as an ESRI world file, so b and d are the rotation/skew terms
+// and will be 0 for north up images.
+func NewAffineConverter(a float64, b float64, c float64, d float64, e float64, f float64) PointConverter {
+	return func(x float64, y float64) (float64, float64) {
+		return a*x + b*y + c, d*x + e*y + f
+	}
+}
+
+// NewWorldFileConverter finds the world file next to the supplied image (eg. image.pgw for image.png) and
+// returns an affine converter built from it. World files reference the centre of the top left pixel which
+// matches the points generated by border.FindContours, so no further offset is required.
+func NewWorldFileConverter(imageFilename string) (PointConverter, error) {
+	worldFilename, err := FindWorldFile(imageFilename)
+	if err != nil {
+		return nil, err
+	}
+	return LoadWorldFile(worldFilename)
+}
+
+// LoadWorldFile reads a world file and returns an affine converter built from it.
+func LoadWorldFile(filename string) (PointConverter, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	t, err := ParseWorldFile(f)
+	if err != nil {
+		return nil, err
+	}
+	return NewAffineConverter(t[0], t[1], t[2], t[3], t[4], t[5]), nil
+}
+
+// ParseWorldFile reads the six lines of a world file and returns the transform parameters in the order
+// used by NewAffineConverter (a, b, c, d, e, f).
+// Note: the file itself stores them in the order a, d, b, e, c, f.
+func ParseWorldFile(r io.Reader) ([6]float64, error) {
+	var values []float64
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		v, err := strconv.ParseFloat(line, 64)
+		if err != nil {
+			return [6]float64{}, fmt.Errorf("invalid world file value %q: %w", line, err)
+		}
+		values = append(values, v)
+	}
+	if err := scanner.Err(); err != nil {
+		return [6]float64{}, err
+	}
+
+	if len(values) != 6 {
+		return [6]float64{}, fmt.Errorf("world file requires 6 values, got %d", len(values))
+	}
+
+	return [6]float64{values[0], values[2], values[4], values[1], values[3], values[5]}, nil
+}
+
+// FindWorldFile determines the filename of the world file for an image.
+// For image.png it checks (in order) image.pgw, image.pngw and image.wld, also trying upper case extensions.
+func FindWorldFile(imageFilename string) (string, error) {
+	ext := filepath.Ext(imageFilename)
+	base := strings.TrimSuffix(imageFilename, ext)
+	ext = strings.TrimPrefix(ext, ".")
+
+	candidates := []string{}
+	if len(ext) >= 2 {
+		candidates = append(candidates, ext[:1]+ext[len(ext)-1:]+"w", ext+"w")
+	}
+	candidates = append(candidates, "wld")
+
+	for _, candidate := range candidates {
+		for _, worldExt := range []string{strings.ToLower(candidate), strings.ToUpper(candidate)} {
+			filename := base + "." + worldExt
+			if _, err := os.Stat(filename); err == nil {
+				return filename, nil
+			}
+		}
+	}
+
+	return "", ErrWorldFileNotFound
+}
@@ -0,0 +1,177 @@
+package converters
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestNewAffineConverter tests north up and rotated transforms.
+func TestNewAffineConverter(t *testing.T) {
+	testCases := []struct {
+		name      string
+		transform [6]float64
+		x         float64
+		y         float64
+		expectedX float64
+		expectedY float64
+	}{
+		{
+			name:      "success north up",
+			transform: [6]float64{0.5, 0, 144.0, 0, -0.25, -37.0},
+			x:         4,
+			y:         8,
+			expectedX: 146.0,
+			expectedY: -39.0,
+		},
+		{
+			name:      "success rotated 90 degrees",
+			transform: [6]float64{0, -1, 10, 1, 0, 20},
+			x:         3,
+			y:         5,
+			expectedX: 5,
+			expectedY: 23,
+		},
+		{
+			name:      "success skewed",
+			transform: [6]float64{1, 0.5, 0, 0.25, -1, 0},
+			x:         2,
+			y:         2,
+			expectedX: 3,
+			expectedY: -1.5,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := tc.transform
+			conv := NewAffineConverter(tr[0], tr[1], tr[2], tr[3], tr[4], tr[5])
+			x, y := conv(tc.x, tc.y)
+			if math.Abs(x-tc.expectedX) > degTolerance || math.Abs(y-tc.expectedY) > degTolerance {
+				t.Errorf("expected %f,%f got %f,%f", tc.expectedX, tc.expectedY, x, y)
+			}
+		})
+	}
+}
+
+// TestParseWorldFile tests the world file line order is converted correctly.
+func TestParseWorldFile(t *testing.T) {
+	testCases := []struct {
+		name      string
+		contents  string
+		expected  [6]float64
+		expectErr bool
+	}{
+		{
+			name:     "success",
+			contents: "0.5\n0.1\n0.2\n-0.5\n100.25\n200.75\n",
+			expected: [6]float64{0.5, 0.2, 100.25, 0.1, -0.5, 200.75},
+		},
+		{
+			name:     "success with whitespace and windows line endings",
+			contents: " 1.0\r\n0.0\r\n0.0\r\n-1.0\r\n\r\n5\r\n6\r\n",
+			expected: [6]float64{1, 0, 5, 0, -1, 6},
+		},
+		{
+			name:      "error with too few lines",
+			contents:  "1\n0\n0\n-1\n5\n",
+			expectErr: true,
+		},
+		{
+			name:      "error with invalid value",
+			contents:  "1\n0\n0\n-1\n5\nabc\n",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			transform, err := ParseWorldFile(strings.NewReader(tc.contents))
+			if tc.expectErr && err == nil {
+				t.Errorf("expected error, got nil")
+			}
+
+			if !tc.expectErr && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+
+			// expected and received error
+			if tc.expectErr && err != nil {
+				return
+			}
+
+			if transform != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, transform)
+			}
+		})
+	}
+}
+
+// TestNewWorldFileConverter tests locating sidecar files next to an image.
+func TestNewWorldFileConverter(t *testing.T) {
+	testCases := []struct {
+		name          string
+		imageFilename string
+		worldFilename string
+		expectErr     bool
+	}{
+		{
+			name:          "success with pgw",
+			imageFilename: "mask.png",
+			worldFilename: "mask.pgw",
+		},
+		{
+			name:          "success with pngw",
+			imageFilename: "mask.png",
+			worldFilename: "mask.pngw",
+		},
+		{
+			name:          "success with wld",
+			imageFilename: "mask.png",
+			worldFilename: "mask.wld",
+		},
+		{
+			name:          "success with upper case tfw",
+			imageFilename: "mask.tif",
+			worldFilename: "mask.TFW",
+		},
+		{
+			name:          "error with no world file",
+			imageFilename: "mask.png",
+			expectErr:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tc.worldFilename != "" {
+				err := os.WriteFile(filepath.Join(dir, tc.worldFilename), []byte("2\n0\n0\n-2\n100\n200\n"), 0644)
+				if err != nil {
+					t.Fatalf("unable to write world file: %v", err)
+				}
+			}
+
+			conv, err := NewWorldFileConverter(filepath.Join(dir, tc.imageFilename))
+			if tc.expectErr && err == nil {
+				t.Errorf("expected error, got nil")
+			}
+
+			if !tc.expectErr && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+
+			// expected and received error
+			if tc.expectErr && err != nil {
+				return
+			}
+
+			x, y := conv(1, 1)
+			if x != 102 || y != 198 {
+				t.Errorf("expected 102,198 got %f,%f", x, y)
+			}
+		})
+	}
+}
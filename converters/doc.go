@@ -10,7 +10,16 @@
 //   NewPixelToLatLongConverter is similarly used if the input image is a map and the output is a GeoJSON geometry.
 //   This is similar to NewSlippyToLatLongConverter but more "fine grain".
 //
+//   NewAffineConverter applies a six parameter affine transform (eg. from a world file). NewWorldFileConverter
+//   locates the .pgw/.pngw/.wld sidecar next to an image and builds the affine converter from it.
+//
 //   ConvertContourToPolygon is a more generic function that takes a generated Contour and converts to a
 //   Geometry. This will be used in combination with NewSlippyToLatLongConverter or NewPixelToLatLongConverter
+//
+//   ConvertContourToPolygonPreservingTopology simplifies without rings crossing each other or themselves,
+//   which geom.Simplify (as used by ConvertContourToPolygon) does not guarantee.
+//
+//   ConvertContourFToPolygon converts the sub-pixel contours from border.FindContoursMarchingSquares, giving
+//   smooth boundaries rather than the staircase of pixel centres.
 
 package converters
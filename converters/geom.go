@@ -1,11 +1,13 @@
 package converters
 
 import (
+	"context"
 	"errors"
 	"image"
 	"math"
 
 	"github.com/kpfaulkner/borders/border"
+	"github.com/kpfaulkner/borders/common"
 	"github.com/peterstace/simplefeatures/geom"
 )
 
@@ -52,13 +54,50 @@ func LatLongToSlippy(latDegrees float64, longDegrees float64, scale int) (float6
 //		multiPolygonOnly: If the geometry is results in a GeometryCollection, then extract out the multipolygon part and return that.
 //		pointConverters: Used to convert point co-ord systems. eg. slippy to lat/long.
 func ConvertContourToPolygon(c *border.Contour, scale int, simplify bool, minPoints int, tolerance float64, multiPolygonOnly bool, pointConverters ...PointConverter) (*geom.Geometry, error) {
+	return ConvertContourToPolygonCtx(context.Background(), c, scale, simplify, minPoints, tolerance, multiPolygonOnly, nil, pointConverters...)
+}
+
+// ConvertContourToPolygonCtx is ConvertContourToPolygon with cancellation and progress reporting. The context is
+// checked before each contour is converted and before simplification; simplification itself can not be interrupted.
+// progress (if not nil) is called after each contour is converted with the ContoursConverted and TotalContours.
+func ConvertContourToPolygonCtx(ctx context.Context, c *border.Contour, scale int, simplify bool, minPoints int, tolerance float64, multiPolygonOnly bool, progress common.ProgressFunc, pointConverters ...PointConverter) (*geom.Geometry, error) {
 	polygons := []geom.Polygon{}
 
-	err := convertContourToPolygons(c, minPoints, &polygons)
+	converted := 0
+	total := 0
+	if progress != nil {
+		total = countContours(c)
+	}
+	report := func() {
+		converted++
+		if progress != nil {
+			progress(common.Progress{ContoursConverted: converted, TotalContours: total})
+		}
+	}
+
+	err := convertContourToPolygons(ctx, c, minPoints, &polygons, report)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return convertPolygons(polygons, scale, simplify, tolerance, multiPolygonOnly, pointConverters...)
+}
+
+// ConvertContourFToPolygon converts the sub-pixel contours found by border.FindContoursMarchingSquares to a
+// MultiPolygon geometry. Parameters are as per ConvertContourToPolygon.
+func ConvertContourFToPolygon(c *border.ContourF, scale int, simplify bool, minPoints int, tolerance float64, multiPolygonOnly bool, pointConverters ...PointConverter) (*geom.Geometry, error) {
+	polygons := []geom.Polygon{}
+	convertContourFToPolygons(c, minPoints, &polygons)
+	return convertPolygons(polygons, scale, simplify, tolerance, multiPolygonOnly, pointConverters...)
+}
+
+// convertPolygons simplifies (if required) then converts the polygons to a MultiPolygon geometry with
+// the PointConverters (if supplied).
+func convertPolygons(polygons []geom.Polygon, scale int, simplify bool, tolerance float64, multiPolygonOnly bool, pointConverters ...PointConverter) (*geom.Geometry, error) {
 	mp := geom.NewMultiPolygon(polygons)
 
 	if simplify {
@@ -103,6 +142,20 @@ func ConvertContourToPolygon(c *border.Contour, scale int, simplify bool, minPoi
 	return returnConvertedGeometry(&mp, pointConverters...)
 }
 
+// ConvertLabelledContoursToPolygons converts the per label contours generated by border.FindLabelledContours
+// into a geometry per label. All parameters are applied to each label as per ConvertContourToPolygon.
+func ConvertLabelledContoursToPolygons(contours map[int]*border.Contour, scale int, simplify bool, minPoints int, tolerance float64, multiPolygonOnly bool, pointConverters ...PointConverter) (map[int]*geom.Geometry, error) {
+	geometries := make(map[int]*geom.Geometry)
+	for label, c := range contours {
+		g, err := ConvertContourToPolygon(c, scale, simplify, minPoints, tolerance, multiPolygonOnly, pointConverters...)
+		if err != nil {
+			return nil, err
+		}
+		geometries[label] = g
+	}
+	return geometries, nil
+}
+
 // returnConvertedGeometry converts the multipolygon with PointConverters (if supplied)
 // Can be used to help convert to lat/long or any other co-ordinate system.
 func returnConvertedGeometry(mp *geom.MultiPolygon, pointConverters ...PointConverter) (*geom.Geometry, error) {
@@ -116,8 +169,13 @@ func returnConvertedGeometry(mp *geom.MultiPolygon, pointConverters ...PointConv
 
 // convertCoords converts the coordinates of a multipolygon using the supplied PointConverters.
 func convertCoords(mp *geom.MultiPolygon, converters ...PointConverter) (*geom.MultiPolygon, error) {
+	mp2 := mp.TransformXY(convertXY(converters...))
+	return &mp2, nil
+}
 
-	mp2 := mp.TransformXY(func(xy geom.XY) geom.XY {
+// convertXY returns a function that runs a co-ord through the supplied PointConverters in order.
+func convertXY(converters ...PointConverter) func(geom.XY) geom.XY {
+	return func(xy geom.XY) geom.XY {
 		x := xy.X
 		y := xy.Y
 		// run through converters.
@@ -127,10 +185,27 @@ func convertCoords(mp *geom.MultiPolygon, converters ...PointConverter) (*geom.M
 			y = newY
 		}
 		return geom.XY{X: x, Y: y}
-	})
+	}
+}
 
-	return &mp2, nil
+// ConvertRotatedRectToPolygon converts a rotated rectangle (eg. from Contour.MinAreaRect) to a polygon,
+// converting the corners with the PointConverters (if supplied).
+func ConvertRotatedRectToPolygon(r border.RotatedRect, pointConverters ...PointConverter) (*geom.Polygon, error) {
+	corners := r.Corners()
+	coords := make([]float64, 0, 10)
+	for _, c := range corners {
+		coords = append(coords, c.X, c.Y)
+	}
+	coords = append(coords, corners[0].X, corners[0].Y)
+
+	ring := geom.NewLineString(geom.NewSequence(coords, geom.DimXY))
+	poly := geom.NewPolygon([]geom.LineString{ring})
+	if err := poly.Validate(); err != nil {
+		return nil, err
+	}
 
+	poly = poly.TransformXY(convertXY(pointConverters...))
+	return &poly, nil
 }
 
 // generateLineString generates a LineString from a slice of image.Points.
@@ -151,7 +226,10 @@ func generateLineString(points []image.Point) (*geom.LineString, error) {
 
 // convertContourToPolygons converts the contour to a set of polygons but does NOT convert to different co-ord systems.
 // If a polygon has fewer than minPoints then it will be discarded. 0 means no min points.
-func convertContourToPolygons(c *border.Contour, minPoints int, polygons *[]geom.Polygon) error {
+func convertContourToPolygons(ctx context.Context, c *border.Contour, minPoints int, polygons *[]geom.Polygon, converted func()) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	// outer... so make a poly
 	// will also cover hole if there.
@@ -185,16 +263,57 @@ func convertContourToPolygons(c *border.Contour, minPoints int, polygons *[]geom
 	for _, child := range c.Children {
 		// only process child if no conflict with parent.
 		if !child.ParentCollision && child.Usable {
-			err := convertContourToPolygons(child, minPoints, polygons)
+			err := convertContourToPolygons(ctx, child, minPoints, polygons, converted)
 			if err != nil {
 				return err
 			}
 		}
 	}
 
+	converted()
 	return nil
 }
 
+// countContours returns the number of contours convertContourToPolygons will visit.
+func countContours(c *border.Contour) int {
+	count := 1
+	for _, child := range c.Children {
+		if !child.ParentCollision && child.Usable {
+			count += countContours(child)
+		}
+	}
+	return count
+}
+
+// convertContourFToPolygons converts the sub-pixel contour to a set of polygons but does NOT convert to different
+// co-ord systems. If a polygon has fewer than minPoints then it will be discarded. 0 means no min points.
+func convertContourFToPolygons(c *border.ContourF, minPoints int, polygons *[]geom.Polygon) {
+	if c.BorderType == border.Outer {
+		lineStrings := []geom.LineString{geom.NewLineString(pointsFToSequence(c.Points))}
+		for _, child := range c.Children {
+			lineStrings = append(lineStrings, geom.NewLineString(pointsFToSequence(child.Points)))
+		}
+
+		if minPoints == 0 || len(lineStrings) > minPoints {
+			*polygons = append(*polygons, geom.NewPolygon(lineStrings))
+		}
+	}
+
+	for _, child := range c.Children {
+		convertContourFToPolygons(child, minPoints, polygons)
+	}
+}
+
+// pointsFToSequence converts a slice of border.PointF to a closed geom.Sequence.
+func pointsFToSequence(points []border.PointF) geom.Sequence {
+	seq := make([]float64, 0, len(points)*2+2)
+	for _, p := range points {
+		seq = append(seq, p.X, p.Y)
+	}
+	seq = append(seq, points[0].X, points[0].Y)
+	return geom.NewSequence(seq, geom.DimXY)
+}
+
 // pointsToSequence converts a slice of image.Points to a geom.Sequence.
 func pointsToSequence(points []image.Point) geom.Sequence {
 	s := len(points)*2 + 2
@@ -278,6 +397,14 @@ func NewPixelToLatLongConverter(topLeftPixelLong float64, topLeftPixelLat float6
 	return f
 }
 
+// WebMercatorToLatLong converts Web Mercator (EPSG:3857) co-ordinates in metres to longitude/latitude.
+// It matches the PointConverter signature so can be used directly as a converter.
+func WebMercatorToLatLong(x float64, y float64) (float64, float64) {
+	long := x / EarthRadius * degreesToRadiansRatio
+	lat := (2*math.Atan(math.Exp(y/EarthRadius)) - math.Pi/2.0) * degreesToRadiansRatio
+	return long, lat
+}
+
 func PixelXYToLatLong(pixelX uint64, pixelY uint64, scale int) (float64, float64) {
 
 	pixelTileSize := 256.0
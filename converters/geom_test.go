@@ -1,11 +1,16 @@
 package converters
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"image"
+	"image/color"
 	"math"
 	"testing"
 
 	"github.com/kpfaulkner/borders/border"
+	"github.com/kpfaulkner/borders/common"
 )
 
 const (
@@ -154,3 +159,225 @@ func TestPixelXYToLatLong(t *testing.T) {
 	lat, lon := PixelXYToLatLong(16123926*2, 199596287*2, 22)
 	fmt.Printf("lat %f, lon %f\n", lat, lon)
 }
+
+func TestConvertLabelledContoursToPolygons(t *testing.T) {
+
+	palette := color.Palette{color.Black, color.White, color.RGBA{255, 0, 0, 255}}
+	img := image.NewPaletted(image.Rect(0, 0, 10, 5), palette)
+	for y := 1; y < 4; y++ {
+		for x := 1; x < 4; x++ {
+			img.SetColorIndex(x, y, 1)
+			img.SetColorIndex(x+5, y, 2)
+		}
+	}
+
+	contours, err := border.FindLabelledContours(img, 0, border.LoadOptions{})
+	if err != nil {
+		t.Fatalf("Unable to find labelled contours: %s", err.Error())
+	}
+
+	polys, err := ConvertLabelledContoursToPolygons(contours, 21, false, 0, 0, false)
+	if err != nil {
+		t.Fatalf("Unable to convert labelled contours: %s", err.Error())
+	}
+
+	expected := map[int]string{
+		1: "MULTIPOLYGON(((1 1,1 2,1 3,2 3,3 3,3 2,3 1,2 1,1 1)))",
+		2: "MULTIPOLYGON(((6 1,6 2,6 3,7 3,8 3,8 2,8 1,7 1,6 1)))",
+	}
+	if len(polys) != len(expected) {
+		t.Fatalf("expected %d geometries, got %d", len(expected), len(polys))
+	}
+	for label, wkt := range expected {
+		if polys[label].AsText() != wkt {
+			t.Errorf("label %d: expected polygon to be %s, got %s", label, wkt, polys[label].AsText())
+		}
+	}
+}
+
+func TestWebMercatorToLatLong(t *testing.T) {
+	testCases := []struct {
+		name        string
+		x           float64
+		y           float64
+		expectedLon float64
+		expectedLat float64
+	}{
+		{
+			name:        "success at origin",
+			expectedLon: 0,
+			expectedLat: 0,
+		},
+		{
+			name:        "success",
+			x:           16107788.0,
+			y:           -4518212.0,
+			expectedLon: 144.698722,
+			expectedLat: -37.565400,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			lon, lat := WebMercatorToLatLong(tc.x, tc.y)
+			if math.Abs(lon-tc.expectedLon) > degTolerance {
+				t.Errorf("expected lon %f, got %f", tc.expectedLon, lon)
+			}
+
+			if math.Abs(lat-tc.expectedLat) > degTolerance {
+				t.Errorf("expected lat %f, got %f", tc.expectedLat, lat)
+			}
+		})
+	}
+}
+
+// TestConvertContourToPolygonCtx tests progress is reported for each contour and a cancelled context stops conversion.
+func TestConvertContourToPolygonCtx(t *testing.T) {
+	testImage, err := border.LoadImage(`../testimages/unittest1.png`, 1, 1)
+	if err != nil {
+		t.Fatalf("Unable to load test image: %s", err.Error())
+	}
+
+	cont, err := border.FindContours(testImage)
+	if err != nil {
+		t.Fatalf("Unable to find contours: %s", err.Error())
+	}
+
+	reports := []common.Progress{}
+	progress := func(p common.Progress) {
+		reports = append(reports, p)
+	}
+	poly, err := ConvertContourToPolygonCtx(context.Background(), cont, 21, true, 0, 0, true, progress)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// every contour is converted (including the root) except those colliding with their parent.
+	total := 0
+	var count func(c *border.Contour)
+	count = func(c *border.Contour) {
+		total++
+		for _, child := range c.Children {
+			if !child.ParentCollision && child.Usable {
+				count(child)
+			}
+		}
+	}
+	count(cont)
+	if len(reports) != total {
+		t.Fatalf("expected %d progress reports, got %d", total, len(reports))
+	}
+	for i, p := range reports {
+		if p.ContoursConverted != i+1 || p.TotalContours != total {
+			t.Errorf("expected %d of %d contours converted, got %+v", i+1, total, p)
+		}
+	}
+
+	expected, err := ConvertContourToPolygon(cont, 21, true, 0, 0, true)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if poly.AsText() != expected.AsText() {
+		t.Errorf("expected %s, got %s", expected.AsText(), poly.AsText())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = ConvertContourToPolygonCtx(ctx, cont, 21, true, 0, 0, true, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+// TestConvertRotatedRectToPolygon tests rectangles are converted with the supplied converters.
+func TestConvertRotatedRectToPolygon(t *testing.T) {
+	testCases := []struct {
+		name       string
+		rect       border.RotatedRect
+		converters []PointConverter
+		expected   string
+		expectErr  bool
+	}{
+		{
+			name:     "success",
+			rect:     border.RotatedRect{Centre: border.PointF{X: 3, Y: 2}, Width: 4, Height: 2},
+			expected: "POLYGON((1 1,1 3,5 3,5 1,1 1))",
+		},
+		{
+			name:       "success with converter",
+			rect:       border.RotatedRect{Centre: border.PointF{X: 3, Y: 2}, Width: 4, Height: 2},
+			converters: []PointConverter{NewAffineConverter(2, 0, 10, 0, -2, 20)},
+			expected:   "POLYGON((12 18,12 14,20 14,20 18,12 18))",
+		},
+		{
+			name:      "error with empty rectangle",
+			rect:      border.RotatedRect{Centre: border.PointF{X: 3, Y: 2}, Width: 4},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			poly, err := ConvertRotatedRectToPolygon(tc.rect, tc.converters...)
+			if tc.expectErr && err == nil {
+				t.Errorf("expected error, got nil")
+			}
+
+			if !tc.expectErr && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+
+			// expected and received error
+			if tc.expectErr && err != nil {
+				return
+			}
+
+			if poly.AsText() != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, poly.AsText())
+			}
+		})
+	}
+}
+
+// TestConvertContourFToPolygon tests sub-pixel contours are converted to polygons with their holes.
+func TestConvertContourFToPolygon(t *testing.T) {
+	testCases := []struct {
+		name       string
+		field      *border.Field
+		converters []PointConverter
+		expected   string
+	}{
+		{
+			name:     "success single pixel",
+			field:    &border.Field{Width: 1, Height: 1, Values: []float64{1}},
+			expected: "MULTIPOLYGON(((0 -0.5,-0.5 0,0 0.5,0.5 0,0 -0.5)))",
+		},
+		{
+			name:       "success ring with hole and converter",
+			field:      &border.Field{Width: 3, Height: 3, Values: []float64{1, 1, 1, 1, 0.25, 1, 1, 1, 1}},
+			converters: []PointConverter{NewAffineConverter(2, 0, 10, 0, 2, 20)},
+			expected:   "MULTIPOLYGON(((10 19,9 20,9 22,9 24,10 25,12 25,14 25,15 24,15 22,15 20,14 19,12 19,10 19),(11.333333333333334 22,12 21.333333333333332,12.666666666666666 22,12 22.666666666666668,11.333333333333334 22)))",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := border.FindContoursMarchingSquares(tc.field, 0.5)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			g, err := ConvertContourFToPolygon(c, 21, false, 0, 0, false, tc.converters...)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if err := g.Validate(); err != nil {
+				t.Errorf("expected valid geometry, got %v", err)
+			}
+			if g.AsText() != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, g.AsText())
+			}
+		})
+	}
+}
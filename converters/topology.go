@@ -0,0 +1,294 @@
+package converters
+
+import (
+	"image"
+	"math"
+
+	"github.com/kpfaulkner/borders/border"
+	"github.com/peterstace/simplefeatures/geom"
+)
+
+// ConvertContourToPolygonPreservingTopology converts the contours to a multipolygon as per ConvertContourToPolygon
+// but simplifies each ring (in pixel space) so that the result is still valid: rings do not cross themselves
+// or each other, holes stay inside their outer ring and siblings do not overlap.
+//
+// Rings are simplified using Douglas-Peucker, except a section of a ring is only replaced by a straight
+// line if no other vertex (of any ring) lies in the area between them. Vertices that are shared between
+// rings, or appear more than once in a ring, are never removed, so rings that touch in the contours still
+// touch at the same points.
+// params:
+//
+//	minPoints: Minimum number of vertices in the simplified outer ring for a polygon to be kept. 0 means no minimum.
+//	tolerance: Tolerance in pixels when simplifying. If set to 0, then will use defaults.
+//	pointConverters: Used to convert point co-ord systems. eg. slippy to lat/long.
+func ConvertContourToPolygonPreservingTopology(c *border.Contour, scale int, minPoints int, tolerance float64, pointConverters ...PointConverter) (*geom.Geometry, error) {
+	if tolerance == 0 {
+		tolerance = generateSimplifyTolerance(scale)
+	}
+
+	polygons := [][][]image.Point{}
+	collectPolygonRings(c, &polygons)
+
+	rings := [][]image.Point{}
+	for _, poly := range polygons {
+		rings = append(rings, poly...)
+	}
+	simplified := simplifyRings(rings, tolerance)
+
+	geomPolygons := []geom.Polygon{}
+	ringIdx := 0
+	for _, poly := range polygons {
+		lineStrings := []geom.LineString{}
+		for range poly {
+			lineStrings = append(lineStrings, geom.NewLineString(pointsToSequence(simplified[ringIdx])))
+			ringIdx++
+		}
+
+		if minPoints == 0 || lineStrings[0].Coordinates().Length()-1 >= minPoints {
+			geomPolygons = append(geomPolygons, geom.NewPolygon(lineStrings))
+		}
+	}
+
+	mp := geom.NewMultiPolygon(geomPolygons)
+	return returnConvertedGeometry(&mp, pointConverters...)
+}
+
+// collectPolygonRings collects the rings (outer followed by holes) of each polygon, using the same rules for
+// which contours are included as convertContourToPolygons.
+func collectPolygonRings(c *border.Contour, polygons *[][][]image.Point) {
+	if c.BorderType == border.Outer && len(c.Points) >= 3 {
+		rings := [][]image.Point{c.Points}
+		for _, child := range c.Children {
+			if !child.ParentCollision && child.Usable && len(child.Points) >= 3 {
+				rings = append(rings, child.Points)
+			}
+		}
+		*polygons = append(*polygons, rings)
+	}
+
+	for _, child := range c.Children {
+		if !child.ParentCollision && child.Usable {
+			collectPolygonRings(child, polygons)
+		}
+	}
+}
+
+// ringVertex identifies a vertex within the set of rings being simplified.
+type ringVertex struct {
+	ring int
+	idx  int
+}
+
+// topologySimplifier holds the state shared between rings while simplifying.
+type topologySimplifier struct {
+	rings     [][]image.Point
+	tolerance float64
+
+	// alive marks the vertices still in each ring, pinned the vertices that can't be removed.
+	alive  [][]bool
+	pinned [][]bool
+	count  []int
+
+	// grid is a spatial index of the alive vertices.
+	cellSize int
+	grid     map[image.Point][]ringVertex
+}
+
+// simplifyRings simplifies each closed ring while ensuring no ring crosses itself or any other ring.
+func simplifyRings(rings [][]image.Point, tolerance float64) [][]image.Point {
+	ts := topologySimplifier{
+		rings:     rings,
+		tolerance: tolerance,
+		alive:     make([][]bool, len(rings)),
+		pinned:    make([][]bool, len(rings)),
+		count:     make([]int, len(rings)),
+		cellSize:  max(8, int(tolerance*4)),
+		grid:      make(map[image.Point][]ringVertex),
+	}
+
+	// pin vertices that appear more than once, within a ring or across rings.
+	seen := make(map[image.Point]int)
+	for _, ring := range rings {
+		for _, p := range ring {
+			seen[p]++
+		}
+	}
+
+	for r, ring := range rings {
+		ts.alive[r] = make([]bool, len(ring))
+		ts.pinned[r] = make([]bool, len(ring))
+		ts.count[r] = len(ring)
+		for i, p := range ring {
+			ts.alive[r][i] = true
+			ts.pinned[r][i] = seen[p] > 1
+			cell := ts.cell(p)
+			ts.grid[cell] = append(ts.grid[cell], ringVertex{r, i})
+		}
+	}
+
+	simplified := make([][]image.Point, len(rings))
+	for r := range rings {
+		ts.simplifyRing(r)
+
+		for i, p := range rings[r] {
+			if ts.alive[r][i] {
+				simplified[r] = append(simplified[r], p)
+			}
+		}
+	}
+	return simplified
+}
+
+// simplifyRing runs the constrained Douglas-Peucker over a single ring.
+func (ts *topologySimplifier) simplifyRing(r int) {
+	ring := ts.rings[r]
+	n := len(ring)
+	if n <= 3 {
+		return
+	}
+
+	// split the ring at the point farthest from the start, as per border.ApproxPolyDP.
+	farthest := 0
+	maxDist := -1
+	for i, p := range ring {
+		d := p.Sub(ring[0])
+		dist := d.X*d.X + d.Y*d.Y
+		if dist > maxDist {
+			maxDist = dist
+			farthest = i
+		}
+	}
+
+	type span struct{ start, end int }
+	stack := []span{{0, farthest}, {farthest, n}}
+	for len(stack) > 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if s.end-s.start < 2 {
+			continue
+		}
+
+		a := ring[s.start]
+		b := ring[s.end%n]
+		maxDist := -1.0
+		split := -1
+		hasPinned := false
+		for i := s.start + 1; i < s.end; i++ {
+			if ts.pinned[r][i] {
+				hasPinned = true
+			}
+			dist := distanceToSegment(ring[i], a, b)
+			if dist > maxDist {
+				maxDist = dist
+				split = i
+			}
+		}
+
+		if maxDist <= ts.tolerance && !hasPinned && ts.count[r]-(s.end-s.start-1) >= 3 && ts.canRemove(r, s.start, s.end) {
+			for i := s.start + 1; i < s.end; i++ {
+				ts.alive[r][i] = false
+			}
+			ts.count[r] -= s.end - s.start - 1
+			continue
+		}
+
+		stack = append(stack, span{s.start, split}, span{split, s.end})
+	}
+}
+
+// canRemove checks no alive vertex (other than those in the span) is within the area between the section of
+// ring r from start to end and the straight line replacing it. If so, the line can't cross any other edge.
+func (ts *topologySimplifier) canRemove(r int, start int, end int) bool {
+	ring := ts.rings[r]
+	n := len(ring)
+
+	// polygon of the removed area.
+	area := make([]image.Point, 0, end-start+1)
+	bounds := image.Rectangle{Min: ring[start], Max: ring[start]}
+	for i := start; i <= end; i++ {
+		p := ring[i%n]
+		area = append(area, p)
+		bounds = bounds.Union(image.Rectangle{Min: p, Max: p.Add(image.Point{1, 1})})
+	}
+
+	minCell := ts.cell(bounds.Min)
+	maxCell := ts.cell(bounds.Max)
+	for cy := minCell.Y; cy <= maxCell.Y; cy++ {
+		for cx := minCell.X; cx <= maxCell.X; cx++ {
+			for _, v := range ts.grid[image.Point{cx, cy}] {
+				if !ts.alive[v.ring][v.idx] {
+					continue
+				}
+				if v.ring == r && (v.idx >= start && v.idx <= end || end == n && v.idx == 0) {
+					continue
+				}
+
+				// vertices at the ends of the new line (eg. where rings touch) can't be crossed by it.
+				p := ts.rings[v.ring][v.idx]
+				if p == area[0] || p == area[len(area)-1] {
+					continue
+				}
+				if p.In(bounds) && pointInOrOnPolygon(p, area) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// cell returns the grid cell containing p.
+func (ts *topologySimplifier) cell(p image.Point) image.Point {
+	return image.Point{floorDiv(p.X, ts.cellSize), floorDiv(p.Y, ts.cellSize)}
+}
+
+// floorDiv divides rounding towards negative infinity.
+func floorDiv(a int, b int) int {
+	q := a / b
+	if a%b != 0 && a < 0 {
+		q--
+	}
+	return q
+}
+
+// pointInOrOnPolygon checks if p is inside or on the boundary of the closed polygon.
+func pointInOrOnPolygon(p image.Point, polygon []image.Point) bool {
+	inside := false
+	n := len(polygon)
+	for i := 0; i < n; i++ {
+		a := polygon[i]
+		b := polygon[(i+1)%n]
+
+		// on the edge.
+		if (b.X-a.X)*(p.Y-a.Y)-(b.Y-a.Y)*(p.X-a.X) == 0 &&
+			min(a.X, b.X) <= p.X && p.X <= max(a.X, b.X) &&
+			min(a.Y, b.Y) <= p.Y && p.Y <= max(a.Y, b.Y) {
+			return true
+		}
+
+		if (a.Y > p.Y) != (b.Y > p.Y) {
+			x := float64(b.X-a.X)*float64(p.Y-a.Y)/float64(b.Y-a.Y) + float64(a.X)
+			if float64(p.X) < x {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// distanceToSegment returns the distance from p to the line segment a-b.
+func distanceToSegment(p image.Point, a image.Point, b image.Point) float64 {
+	dx := float64(b.X - a.X)
+	dy := float64(b.Y - a.Y)
+	px := float64(p.X - a.X)
+	py := float64(p.Y - a.Y)
+
+	lengthSq := dx*dx + dy*dy
+	t := 0.0
+	if lengthSq > 0 {
+		t = max(0, min(1, (px*dx+py*dy)/lengthSq))
+	}
+	ex := px - t*dx
+	ey := py - t*dy
+	return math.Sqrt(ex*ex + ey*ey)
+}
@@ -0,0 +1,164 @@
+package converters
+
+import (
+	"image"
+	"testing"
+
+	"github.com/kpfaulkner/borders/border"
+)
+
+// newTestContour creates a contour with the supplied points, adding it as a child of parent (if not nil).
+func newTestContour(id int, borderType int, parent *border.Contour, points ...image.Point) *border.Contour {
+	c := border.NewContour(id)
+	c.BorderType = borderType
+	c.Points = points
+	if parent != nil {
+		c.Parent = parent
+		c.ParentId = parent.Id
+		parent.Children = append(parent.Children, c)
+	}
+	return c
+}
+
+// TestConvertContourToPolygonPreservingTopology tests simplifying contours that would otherwise be invalid.
+func TestConvertContourToPolygonPreservingTopology(t *testing.T) {
+	testCases := []struct {
+		name      string
+		contour   func() *border.Contour
+		tolerance float64
+		expected  string
+	}{
+		{
+			name: "success hole inside bump is kept inside",
+			contour: func() *border.Contour {
+				root := border.NewContour(1)
+				outer := newTestContour(2, border.Outer, root,
+					image.Point{0, 10}, image.Point{0, 20}, image.Point{20, 20}, image.Point{20, 10},
+					image.Point{14, 10}, image.Point{10, 6}, image.Point{6, 10})
+				newTestContour(3, border.Hole, outer, image.Point{9, 8}, image.Point{11, 8}, image.Point{10, 9})
+				return root
+			},
+			tolerance: 5,
+			expected:  "MULTIPOLYGON(((0 10,0 20,20 20,20 10,10 6,0 10),(9 8,11 8,10 9,9 8)))",
+		},
+		{
+			name: "success sibling inside notch doesn't overlap",
+			contour: func() *border.Contour {
+				root := border.NewContour(1)
+				newTestContour(2, border.Outer, root,
+					image.Point{0, 0}, image.Point{0, 10}, image.Point{10, 10}, image.Point{10, 7},
+					image.Point{6, 5}, image.Point{10, 3}, image.Point{10, 0})
+				newTestContour(3, border.Outer, root,
+					image.Point{11, 0}, image.Point{11, 4}, image.Point{8, 5}, image.Point{11, 6},
+					image.Point{11, 10}, image.Point{20, 10}, image.Point{20, 0})
+				return root
+			},
+			tolerance: 5,
+			// the first ring keeps the notch (as the bump of the second is in it), the bump can then be removed.
+			expected: "MULTIPOLYGON(((0 0,0 10,10 10,6 5,10 0,0 0)),((11 0,11 10,20 10,20 0,11 0)))",
+		},
+		{
+			name: "success touching rings keep shared vertex",
+			contour: func() *border.Contour {
+				root := border.NewContour(1)
+				newTestContour(2, border.Outer, root,
+					image.Point{0, 0}, image.Point{0, 5}, image.Point{5, 5}, image.Point{5, 4}, image.Point{5, 3}, image.Point{5, 0})
+				newTestContour(3, border.Outer, root,
+					image.Point{5, 4}, image.Point{6, 8}, image.Point{10, 8}, image.Point{10, 4})
+				return root
+			},
+			tolerance: 2,
+			expected:  "MULTIPOLYGON(((0 0,0 5,5 5,5 4,5 0,0 0)),((5 4,6 8,10 8,10 4,5 4)))",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g, err := ConvertContourToPolygonPreservingTopology(tc.contour(), 21, 0, tc.tolerance)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if err := g.Validate(); err != nil {
+				t.Errorf("expected valid geometry, got %v", err)
+			}
+
+			if g.AsText() != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, g.AsText())
+			}
+		})
+	}
+}
+
+// TestSimplifyRingsDoesNotCross tests simplifying the contours of real images doesn't generate any crossing edges.
+func TestSimplifyRingsDoesNotCross(t *testing.T) {
+	testCases := []struct {
+		name      string
+		filename  string
+		tolerance float64
+	}{
+		{name: "success unittest1", filename: "../testimages/unittest1.png", tolerance: 2},
+		{name: "success image1", filename: "../testimages/image1.png", tolerance: 2},
+		{name: "success image1 with large tolerance", filename: "../testimages/image1.png", tolerance: 20},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			img, err := border.LoadImage(tc.filename, 0, 0)
+			if err != nil {
+				t.Fatalf("Unable to load test image: %s", err.Error())
+			}
+
+			c, err := border.FindContours(img)
+			if err != nil {
+				t.Fatalf("Unable to find contours: %s", err.Error())
+			}
+
+			polygons := [][][]image.Point{}
+			collectPolygonRings(c, &polygons)
+			rings := [][]image.Point{}
+			for _, poly := range polygons {
+				rings = append(rings, poly...)
+			}
+
+			simplified := simplifyRings(rings, tc.tolerance)
+
+			type segment struct{ a, b image.Point }
+			segments := []segment{}
+			for i, ring := range simplified {
+				if len(ring) < 3 && len(rings[i]) >= 3 {
+					t.Fatalf("expected ring %d to keep at least 3 points, got %d", i, len(ring))
+				}
+				for j, p := range ring {
+					segments = append(segments, segment{p, ring[(j+1)%len(ring)]})
+				}
+			}
+
+			for i, s1 := range segments {
+				for _, s2 := range segments[i+1:] {
+					if segmentsCross(s1.a, s1.b, s2.a, s2.b) {
+						t.Fatalf("simplified segments %v-%v and %v-%v cross", s1.a, s1.b, s2.a, s2.b)
+					}
+				}
+			}
+		})
+	}
+}
+
+// segmentsCross checks if two segments properly cross (touching at end points is allowed).
+func segmentsCross(a image.Point, b image.Point, c image.Point, d image.Point) bool {
+	orient := func(p image.Point, q image.Point, r image.Point) int {
+		v := (q.X-p.X)*(r.Y-p.Y) - (q.Y-p.Y)*(r.X-p.X)
+		switch {
+		case v > 0:
+			return 1
+		case v < 0:
+			return -1
+		}
+		return 0
+	}
+
+	o1, o2 := orient(a, b, c), orient(a, b, d)
+	o3, o4 := orient(c, d, a), orient(c, d, b)
+	return o1*o2 < 0 && o3*o4 < 0
+}
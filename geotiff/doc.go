@@ -0,0 +1,13 @@
+// Package geotiff reads GeoTIFF masks and generates both the SuzukiImage used for border detection and
+// a PointConverter built from the georeferencing embedded in the file.
+//
+// The key function is:
+//   Load: Reads a GeoTIFF file and returns the SuzukiImage, the PointConverter and the GeoInfo
+//   describing the affine transform and EPSG code found in the file. The PointConverter can be passed
+//   directly to converters.ConvertContourToPolygon so no manual offsets are required.
+//
+// The ModelTiepoint/ModelPixelScale and ModelTransformation tags are supported. If the EPSG code
+// is Web Mercator (3857) the PointConverter returns longitude/latitude, otherwise it returns coordinates
+// in the CRS of the file.
+
+package geotiff
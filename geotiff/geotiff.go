@@ -0,0 +1,292 @@
+package geotiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	"github.com/kpfaulkner/borders/border"
+	"github.com/kpfaulkner/borders/common"
+	"github.com/kpfaulkner/borders/converters"
+	"golang.org/x/image/tiff"
+)
+
+const (
+
+	// TIFF tags holding georeferencing information.
+	tagModelPixelScale     = 33550
+	tagModelTiepoint       = 33922
+	tagModelTransformation = 34264
+	tagGeoKeyDirectory     = 34735
+
+	// GeoKeys that we're interested in.
+	keyRasterType          = 1025
+	keyGeographicType      = 2048
+	keyProjectedCSType     = 3072
+	rasterPixelIsPoint     = 2
+	userDefinedGeoKeyValue = 32767
+
+	// TIFF field types.
+	typeShort  = 3
+	typeDouble = 12
+
+	// EPSG codes that we know how to convert to latitude/longitude.
+	EPSGWGS84       = 4326
+	EPSGWebMercator = 3857
+)
+
+var (
+	ErrNotGeoTIFF = errors.New("no georeferencing tags found")
+)
+
+// GeoInfo describes the georeferencing read from a GeoTIFF.
+type GeoInfo struct {
+
+	// Transform is the affine transform from raster space to model space, where
+	// modelX = A*x + B*y + C and modelY = D*x + E*y + F. Stored as A,B,C,D,E,F.
+	Transform [6]float64
+
+	// EPSG code of the model space. 0 if not specified in the file.
+	EPSG int
+
+	// PixelIsPoint indicates raster space refers to pixel centres rather than the top left corner of a pixel.
+	PixelIsPoint bool
+}
+
+// Load reads a GeoTIFF file and returns the SuzukiImage, a PointConverter that maps contour points to
+// model space (or longitude/latitude for Web Mercator files) and the georeferencing details.
+func Load(filename string, opts border.LoadOptions) (*common.SuzukiImage, converters.PointConverter, *GeoInfo, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer f.Close()
+
+	return LoadFromReader(f, opts)
+}
+
+// LoadFromReader reads a GeoTIFF from r. See Load.
+func LoadFromReader(r io.Reader, opts border.LoadOptions) (*common.SuzukiImage, converters.PointConverter, *GeoInfo, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	info, err := readGeoInfo(data)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	img, err := tiff.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	si, err := border.LoadFromImage(img, opts)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return si, info.PointConverter(), info, nil
+}
+
+// PointConverter returns a converter from contour points (pixel centres) to model space.
+// If the model space is Web Mercator then the result is converted to longitude/latitude.
+func (g *GeoInfo) PointConverter() converters.PointConverter {
+
+	// contour points are pixel centres, whereas in PixelIsArea raster space 0,0 is the top left corner of the pixel.
+	pixelOffset := 0.5
+	if g.PixelIsPoint {
+		pixelOffset = 0
+	}
+
+	// fold the pixel offset into the translation terms.
+	t := g.Transform
+	affine := converters.NewAffineConverter(t[0], t[1], t[2]+(t[0]+t[1])*pixelOffset, t[3], t[4], t[5]+(t[3]+t[4])*pixelOffset)
+	if g.EPSG != EPSGWebMercator {
+		return affine
+	}
+
+	return func(x float64, y float64) (float64, float64) {
+		return converters.WebMercatorToLatLong(affine(x, y))
+	}
+}
+
+// tiffReader reads values from the raw TIFF data with the correct byte order.
+type tiffReader struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+// ifdEntry is a single tag from the image file directory.
+type ifdEntry struct {
+	tag      uint16
+	datatype uint16
+	count    uint32
+	offset   uint32 // offset of the value, or of the value itself if it fits in 4 bytes.
+}
+
+// readGeoInfo parses the first image file directory of the TIFF and extracts the georeferencing tags.
+func readGeoInfo(data []byte) (*GeoInfo, error) {
+	if len(data) < 8 {
+		return nil, errors.New("invalid tiff header")
+	}
+
+	r := tiffReader{data: data}
+	switch string(data[0:4]) {
+	case "II\x2A\x00":
+		r.order = binary.LittleEndian
+	case "MM\x00\x2A":
+		r.order = binary.BigEndian
+	default:
+		return nil, errors.New("invalid tiff header (BigTIFF is not supported)")
+	}
+
+	entries, err := r.readIFD(r.order.Uint32(data[4:8]))
+	if err != nil {
+		return nil, err
+	}
+
+	info := GeoInfo{}
+	var scale, tiepoint, transformation []float64
+	var geoKeys []uint16
+	for _, e := range entries {
+		switch e.tag {
+		case tagModelPixelScale:
+			scale, err = r.readDoubles(e)
+		case tagModelTiepoint:
+			tiepoint, err = r.readDoubles(e)
+		case tagModelTransformation:
+			transformation, err = r.readDoubles(e)
+		case tagGeoKeyDirectory:
+			geoKeys, err = r.readShorts(e)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case len(transformation) >= 16:
+		info.Transform = [6]float64{transformation[0], transformation[1], transformation[3], transformation[4], transformation[5], transformation[7]}
+	case len(tiepoint) >= 6 && len(scale) >= 2:
+		i, j, x, y := tiepoint[0], tiepoint[1], tiepoint[3], tiepoint[4]
+		info.Transform = [6]float64{scale[0], 0, x - i*scale[0], 0, -scale[1], y + j*scale[1]}
+	default:
+		return nil, ErrNotGeoTIFF
+	}
+
+	readGeoKeys(geoKeys, &info)
+	return &info, nil
+}
+
+// readGeoKeys extracts the EPSG code and raster type from the GeoKeyDirectory.
+// Only keys stored directly in the directory are used, which covers the keys we're interested in.
+func readGeoKeys(keys []uint16, info *GeoInfo) {
+	if len(keys) < 4 {
+		return
+	}
+
+	numKeys := int(keys[3])
+	for k := 0; k < numKeys && 4+k*4+3 < len(keys); k++ {
+		entry := keys[4+k*4 : 4+k*4+4]
+		id, location, value := entry[0], entry[1], entry[3]
+		if location != 0 {
+			continue
+		}
+
+		switch id {
+		case keyRasterType:
+			info.PixelIsPoint = value == rasterPixelIsPoint
+		case keyProjectedCSType:
+			if value != userDefinedGeoKeyValue {
+				info.EPSG = int(value)
+			}
+		case keyGeographicType:
+			// projected CRS takes priority over the geographic CRS it is based on.
+			if info.EPSG == 0 && value != userDefinedGeoKeyValue {
+				info.EPSG = int(value)
+			}
+		}
+	}
+}
+
+// readIFD reads all entries of the image file directory at offset.
+func (r *tiffReader) readIFD(offset uint32) ([]ifdEntry, error) {
+	if int(offset)+2 > len(r.data) {
+		return nil, errors.New("invalid tiff directory offset")
+	}
+
+	numEntries := int(r.order.Uint16(r.data[offset:]))
+	start := int(offset) + 2
+	if start+numEntries*12 > len(r.data) {
+		return nil, errors.New("invalid tiff directory")
+	}
+
+	entries := make([]ifdEntry, numEntries)
+	for i := 0; i < numEntries; i++ {
+		b := r.data[start+i*12:]
+		entries[i] = ifdEntry{
+			tag:      r.order.Uint16(b[0:2]),
+			datatype: r.order.Uint16(b[2:4]),
+			count:    r.order.Uint32(b[4:8]),
+			offset:   r.order.Uint32(b[8:12]),
+		}
+	}
+	return entries, nil
+}
+
+// valueBytes returns the raw bytes of an entry's value.
+func (r *tiffReader) valueBytes(e ifdEntry, size int) ([]byte, error) {
+	length := int(e.count) * size
+	if length <= 4 {
+		b := make([]byte, 4)
+		r.order.PutUint32(b, e.offset)
+		return b[:length], nil
+	}
+
+	if int(e.offset)+length > len(r.data) {
+		return nil, fmt.Errorf("invalid value offset for tag %d", e.tag)
+	}
+	return r.data[e.offset : int(e.offset)+length], nil
+}
+
+// readDoubles reads an entry of type DOUBLE.
+func (r *tiffReader) readDoubles(e ifdEntry) ([]float64, error) {
+	if e.datatype != typeDouble {
+		return nil, fmt.Errorf("unexpected type %d for tag %d", e.datatype, e.tag)
+	}
+
+	b, err := r.valueBytes(e, 8)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]float64, e.count)
+	for i := range values {
+		values[i] = math.Float64frombits(r.order.Uint64(b[i*8:]))
+	}
+	return values, nil
+}
+
+// readShorts reads an entry of type SHORT.
+func (r *tiffReader) readShorts(e ifdEntry) ([]uint16, error) {
+	if e.datatype != typeShort {
+		return nil, fmt.Errorf("unexpected type %d for tag %d", e.datatype, e.tag)
+	}
+
+	b, err := r.valueBytes(e, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]uint16, e.count)
+	for i := range values {
+		values[i] = r.order.Uint16(b[i*2:])
+	}
+	return values, nil
+}
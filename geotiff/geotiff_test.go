@@ -0,0 +1,225 @@
+package geotiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/kpfaulkner/borders/border"
+	"github.com/kpfaulkner/borders/converters"
+)
+
+const (
+	tolerance = 0.000001
+)
+
+// TestLoadFromReader tests reading georeferenced GeoTIFFs and converting contour points.
+func TestLoadFromReader(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		scale          []float64
+		tiepoint       []float64
+		transformation []float64
+		geoKeys        []uint16
+
+		expectedInfo GeoInfo
+
+		// pixel to convert, and the expected result.
+		pixel    [2]float64
+		expected [2]float64
+
+		expectErr bool
+	}{
+		{
+			name:         "success with tiepoint and scale",
+			scale:        []float64{2, 3, 0},
+			tiepoint:     []float64{0, 0, 0, 1000, 5000, 0},
+			geoKeys:      []uint16{1, 1, 0, 2, 1025, 0, 1, 1, 3072, 0, 1, 28355},
+			expectedInfo: GeoInfo{Transform: [6]float64{2, 0, 1000, 0, -3, 5000}, EPSG: 28355},
+			pixel:        [2]float64{1, 1},
+			expected:     [2]float64{1003, 4995.5},
+		},
+		{
+			name:         "success with pixel is point",
+			scale:        []float64{2, 3, 0},
+			tiepoint:     []float64{0, 0, 0, 1000, 5000, 0},
+			geoKeys:      []uint16{1, 1, 0, 2, 1025, 0, 1, 2, 2048, 0, 1, 4326},
+			expectedInfo: GeoInfo{Transform: [6]float64{2, 0, 1000, 0, -3, 5000}, EPSG: 4326, PixelIsPoint: true},
+			pixel:        [2]float64{1, 1},
+			expected:     [2]float64{1002, 4997},
+		},
+		{
+			name:           "success with model transformation",
+			transformation: []float64{1, 0.5, 0, 100, 0.25, -1, 0, 200, 0, 0, 0, 0, 0, 0, 0, 1},
+			geoKeys:        []uint16{1, 1, 0, 1, 1025, 0, 1, 2},
+			expectedInfo:   GeoInfo{Transform: [6]float64{1, 0.5, 100, 0.25, -1, 200}, PixelIsPoint: true},
+			pixel:          [2]float64{2, 4},
+			expected:       [2]float64{104, 196.5},
+		},
+		{
+			name:         "success with web mercator",
+			scale:        []float64{1, 1, 0},
+			tiepoint:     []float64{0, 0, 0, 0, 0, 0},
+			geoKeys:      []uint16{1, 1, 0, 2, 1025, 0, 1, 2, 3072, 0, 1, 3857},
+			expectedInfo: GeoInfo{Transform: [6]float64{1, 0, 0, 0, -1, 0}, EPSG: 3857, PixelIsPoint: true},
+			pixel:        [2]float64{0, 0},
+			expected:     [2]float64{0, 0},
+		},
+		{
+			name:      "error with no georeferencing",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data := encodeTestGeoTIFF(t, tc.scale, tc.tiepoint, tc.transformation, tc.geoKeys)
+			si, conv, info, err := LoadFromReader(bytes.NewReader(data), border.LoadOptions{})
+			if tc.expectErr && err == nil {
+				t.Errorf("expected error, got nil")
+			}
+
+			if !tc.expectErr && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			// expected and received error
+			if tc.expectErr && err != nil {
+				return
+			}
+
+			if *info != tc.expectedInfo {
+				t.Errorf("expected info %+v, got %+v", tc.expectedInfo, *info)
+			}
+
+			if si.Width != 4 || si.Height != 4 || si.GetXY(1, 1) != 1 || si.GetXY(0, 0) != 0 {
+				t.Errorf("unexpected image data %v", si.GetAllData())
+			}
+
+			x, y := conv(tc.pixel[0], tc.pixel[1])
+			if math.Abs(x-tc.expected[0]) > tolerance || math.Abs(y-tc.expected[1]) > tolerance {
+				t.Errorf("expected %v, got %f,%f", tc.expected, x, y)
+			}
+		})
+	}
+}
+
+// TestConvertContourToPolygon checks the converter can be used directly when generating polygons.
+func TestConvertContourToPolygon(t *testing.T) {
+	data := encodeTestGeoTIFF(t, []float64{10, 10, 0}, []float64{0, 0, 0, 500000, 6000000, 0}, nil, []uint16{1, 1, 0, 1, 3072, 0, 1, 28355})
+	si, conv, _, err := LoadFromReader(bytes.NewReader(data), border.LoadOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	cont, err := border.FindContours(si)
+	if err != nil {
+		t.Fatalf("Unable to find contours: %s", err.Error())
+	}
+
+	poly, err := converters.ConvertContourToPolygon(cont, 21, false, 0, 0, false, conv)
+	if err != nil {
+		t.Fatalf("Unable to convert to polygon: %s", err.Error())
+	}
+
+	expected := "MULTIPOLYGON(((500015 5999985,500015 5999975,500025 5999975,500025 5999985,500015 5999985)))"
+	if poly.AsText() != expected {
+		t.Errorf("expected polygon to be %s, got %s", expected, poly.AsText())
+	}
+}
+
+// encodeTestGeoTIFF generates a little endian 4x4 8 bit grayscale TIFF with a 2x2 white square at 1,1
+// and the supplied georeferencing tags. Nil slices are not written.
+func encodeTestGeoTIFF(t *testing.T, scale []float64, tiepoint []float64, transformation []float64, geoKeys []uint16) []byte {
+	t.Helper()
+
+	const width, height = 4, 4
+	pix := make([]byte, width*height)
+	for _, i := range []int{5, 6, 9, 10} {
+		pix[i] = 255
+	}
+
+	type entry struct {
+		tag      uint16
+		datatype uint16
+		count    uint32
+		data     []byte
+	}
+
+	shorts := func(v ...uint16) []byte {
+		b := make([]byte, len(v)*2)
+		for i, s := range v {
+			binary.LittleEndian.PutUint16(b[i*2:], s)
+		}
+		return b
+	}
+	longs := func(v ...uint32) []byte {
+		b := make([]byte, len(v)*4)
+		for i, l := range v {
+			binary.LittleEndian.PutUint32(b[i*4:], l)
+		}
+		return b
+	}
+	doubles := func(v []float64) []byte {
+		b := make([]byte, len(v)*8)
+		for i, d := range v {
+			binary.LittleEndian.PutUint64(b[i*8:], math.Float64bits(d))
+		}
+		return b
+	}
+
+	// pixel data immediately follows the header.
+	pixOffset := uint32(8)
+	entries := []entry{
+		{256, 3, 1, shorts(width)},
+		{257, 3, 1, shorts(height)},
+		{258, 3, 1, shorts(8)},
+		{259, 3, 1, shorts(1)},
+		{262, 3, 1, shorts(1)},
+		{273, 4, 1, longs(pixOffset)},
+		{277, 3, 1, shorts(1)},
+		{278, 3, 1, shorts(height)},
+		{279, 4, 1, longs(uint32(len(pix)))},
+	}
+	if scale != nil {
+		entries = append(entries, entry{tagModelPixelScale, typeDouble, uint32(len(scale)), doubles(scale)})
+	}
+	if tiepoint != nil {
+		entries = append(entries, entry{tagModelTiepoint, typeDouble, uint32(len(tiepoint)), doubles(tiepoint)})
+	}
+	if transformation != nil {
+		entries = append(entries, entry{tagModelTransformation, typeDouble, uint32(len(transformation)), doubles(transformation)})
+	}
+	if geoKeys != nil {
+		entries = append(entries, entry{tagGeoKeyDirectory, typeShort, uint32(len(geoKeys)), shorts(geoKeys...)})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].tag < entries[j].tag })
+
+	ifdOffset := pixOffset + uint32(len(pix))
+	ifdSize := uint32(2 + len(entries)*12 + 4)
+	extraOffset := ifdOffset + ifdSize
+
+	var buf, extra bytes.Buffer
+	buf.WriteString("II\x2A\x00")
+	buf.Write(longs(ifdOffset))
+	buf.Write(pix)
+	buf.Write(shorts(uint16(len(entries))))
+	for _, e := range entries {
+		buf.Write(shorts(e.tag, e.datatype))
+		buf.Write(longs(e.count))
+		if len(e.data) <= 4 {
+			value := make([]byte, 4)
+			copy(value, e.data)
+			buf.Write(value)
+			continue
+		}
+		buf.Write(longs(extraOffset + uint32(extra.Len())))
+		extra.Write(e.data)
+	}
+	buf.Write(longs(0))
+	buf.Write(extra.Bytes())
+	return buf.Bytes()
+}
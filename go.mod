@@ -4,7 +4,5 @@ go 1.24.0
 
 require (
 	github.com/peterstace/simplefeatures v0.47.0
-	github.com/sirupsen/logrus v1.9.3
+	golang.org/x/image v0.34.0
 )
-
-require golang.org/x/sys v0.0.0-20220715151400-c0bba94af5f8 // indirect
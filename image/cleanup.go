@@ -0,0 +1,49 @@
+package image
+
+import (
+	"github.com/kpfaulkner/borders/common"
+)
+
+// FillHoles fills the holes in the suzuki image, ie. areas of background not connected to the edge of the image.
+// If maxArea is greater than 0 only holes of up to maxArea pixels are filled.
+// Connectivity is that of the foreground, so the holes use the opposite (as per FindContours).
+func FillHoles(img *common.SuzukiImage, maxArea int, connectivity common.Connectivity) (*common.SuzukiImage, error) {
+	backgroundConnectivity := common.Connectivity4
+	if connectivity == common.Connectivity4 {
+		backgroundConnectivity = common.Connectivity8
+	}
+
+	labels := labelPixels(img, 0, false, backgroundConnectivity)
+	fill := make([]bool, len(labels.Stats)+1)
+	for _, stats := range labels.Stats {
+		b := stats.Bounds
+		touchesEdge := b.Min.X == 0 || b.Min.Y == 0 || b.Max.X == img.Width || b.Max.Y == img.Height
+		fill[stats.Label] = !touchesEdge && (maxArea <= 0 || stats.Area <= maxArea)
+	}
+
+	return relabel(img, labels, fill, 1), nil
+}
+
+// RemoveSmallObjects removes the connected components of the suzuki image with fewer than minArea pixels.
+func RemoveSmallObjects(img *common.SuzukiImage, minArea int, connectivity common.Connectivity) (*common.SuzukiImage, error) {
+	labels := labelPixels(img, 0, true, connectivity)
+	remove := make([]bool, len(labels.Stats)+1)
+	for _, stats := range labels.Stats {
+		remove[stats.Label] = stats.Area < minArea
+	}
+
+	return relabel(img, labels, remove, 0), nil
+}
+
+// relabel returns a copy of the image with every pixel of the selected labels set to val.
+func relabel(img *common.SuzukiImage, labels *Labels, selected []bool, val int) *common.SuzukiImage {
+	img2 := img.Clone()
+	for y := 0; y < labels.Height; y++ {
+		for x := 0; x < labels.Width; x++ {
+			if selected[labels.At(x, y)] {
+				img2.SetXY(x, y, val)
+			}
+		}
+	}
+	return img2
+}
@@ -0,0 +1,157 @@
+package image
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/kpfaulkner/borders/common"
+)
+
+// createRowsSuzukiImage creates a SuzukiImage where '#' is populated.
+func createRowsSuzukiImage(rows []string) *common.SuzukiImage {
+	si := common.NewSuzukiImage(len(rows[0]), len(rows), false)
+	for y, row := range rows {
+		for x, ch := range row {
+			if ch == '#' {
+				si.SetXY(x, y, 1)
+			}
+		}
+	}
+	return si
+}
+
+// TestFillHoles tests holes are filled, limited by area and connectivity.
+func TestFillHoles(t *testing.T) {
+	rows := []string{
+		"..........",
+		".####.###.",
+		".#..#.#.#.",
+		".#..#.###.",
+		".####.....",
+		"......##..",
+		".....#.#..",
+		"......##..",
+		"..........",
+	}
+
+	testCases := []struct {
+		name         string
+		maxArea      int
+		connectivity common.Connectivity
+		expected     []string
+	}{
+		{
+			name:         "success fill all",
+			connectivity: common.Connectivity8,
+			expected: []string{
+				"..........",
+				".####.###.",
+				".####.###.",
+				".####.###.",
+				".####.....",
+				"......##..",
+				".....###..",
+				"......##..",
+				"..........",
+			},
+		},
+		{
+			name:         "success only small holes",
+			maxArea:      1,
+			connectivity: common.Connectivity8,
+			expected: []string{
+				"..........",
+				".####.###.",
+				".#..#.###.",
+				".#..#.###.",
+				".####.....",
+				"......##..",
+				".....###..",
+				"......##..",
+				"..........",
+			},
+		},
+		{
+			name:         "success 4 connected hole leaks diagonally",
+			connectivity: common.Connectivity4,
+			expected: []string{
+				"..........",
+				".####.###.",
+				".####.###.",
+				".####.###.",
+				".####.....",
+				"......##..",
+				".....#.#..",
+				"......##..",
+				"..........",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			img := createRowsSuzukiImage(rows)
+			filled, err := FillHoles(img, tc.maxArea, tc.connectivity)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if !slices.Equal(filled.GetAllData(), createRowsSuzukiImage(tc.expected).GetAllData()) {
+				t.Errorf("expected %v, got %v", tc.expected, filled.DisplayAsText())
+			}
+			if !slices.Equal(img.GetAllData(), createRowsSuzukiImage(rows).GetAllData()) {
+				t.Errorf("expected original image to be unchanged")
+			}
+		})
+	}
+}
+
+// TestRemoveSmallObjects tests components with fewer than the minimum pixels are removed.
+func TestRemoveSmallObjects(t *testing.T) {
+	rows := []string{
+		".......",
+		".##..#.",
+		".##.#..",
+		".....#.",
+		"#......",
+	}
+
+	testCases := []struct {
+		name         string
+		minArea      int
+		connectivity common.Connectivity
+		expected     []string
+	}{
+		{
+			name:         "success 8 connected",
+			minArea:      3,
+			connectivity: common.Connectivity8,
+			expected:     []string{".......", ".##..#.", ".##.#..", ".....#.", "......."},
+		},
+		{
+			name:         "success 4 connected",
+			minArea:      2,
+			connectivity: common.Connectivity4,
+			expected:     []string{".......", ".##....", ".##....", ".......", "......."},
+		},
+		{
+			name:         "success nothing removed",
+			minArea:      1,
+			connectivity: common.Connectivity8,
+			expected:     rows,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			removed, err := RemoveSmallObjects(createRowsSuzukiImage(rows), tc.minArea, tc.connectivity)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if !slices.Equal(removed.GetAllData(), createRowsSuzukiImage(tc.expected).GetAllData()) {
+				t.Errorf("expected %v, got %v", tc.expected, removed.DisplayAsText())
+			}
+		})
+	}
+}
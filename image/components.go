@@ -0,0 +1,150 @@
+package image
+
+import (
+	"image"
+
+	"github.com/kpfaulkner/borders/common"
+)
+
+// ComponentStats describes a single connected component.
+type ComponentStats struct {
+
+	// Label of the component, starting from 1.
+	Label int
+
+	// Area is the number of pixels in the component.
+	Area int
+
+	// Bounds of the component. Max is exclusive.
+	Bounds image.Rectangle
+
+	// CentroidX and CentroidY are the mean of the pixel co-ords.
+	CentroidX float64
+	CentroidY float64
+}
+
+// Labels is the connected component label of every pixel of an image.
+type Labels struct {
+	Width  int
+	Height int
+
+	// Labels for each pixel (row by row), 0 for background.
+	Labels []int
+
+	// Stats for each component, Stats[label-1] being for label.
+	Stats []ComponentStats
+}
+
+// At returns the label at x,y.
+func (l *Labels) At(x int, y int) int {
+	return l.Labels[y*l.Width+x]
+}
+
+// LabelComponents labels the connected components of the populated pixels using a two pass union-find.
+// Labels are numbered in the order components are first encountered, scanning row by row, which is the order
+// FindContours finds their outer borders. Padding is removed so the co-ords match the contours.
+func LabelComponents(img *common.SuzukiImage, connectivity common.Connectivity) *Labels {
+	offset := 0
+	if img.HasPadding() {
+		offset = 1
+	}
+	return labelPixels(img, offset, true, connectivity)
+}
+
+// labelPixels labels the connected foreground (or background) pixels of the image, ignoring offset pixels
+// around the edge.
+func labelPixels(img *common.SuzukiImage, offset int, foreground bool, connectivity common.Connectivity) *Labels {
+	width := img.Width - 2*offset
+	height := img.Height - 2*offset
+
+	// previously scanned neighbours.
+	neighbours := []image.Point{{-1, 0}, {0, -1}}
+	if connectivity != common.Connectivity4 {
+		neighbours = append(neighbours, image.Point{-1, -1}, image.Point{1, -1})
+	}
+
+	// first pass, provisional labels with the equivalences held in parents.
+	labels := make([]int, width*height)
+	parents := []int{0}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if (img.GetXY(x+offset, y+offset) != 0) != foreground {
+				continue
+			}
+
+			label := 0
+			for _, d := range neighbours {
+				nx, ny := x+d.X, y+d.Y
+				if nx < 0 || ny < 0 || nx >= width {
+					continue
+				}
+				n := labels[ny*width+nx]
+				if n == 0 {
+					continue
+				}
+				if label == 0 {
+					label = findRoot(parents, n)
+				} else {
+					label = union(parents, label, n)
+				}
+			}
+
+			if label == 0 {
+				label = len(parents)
+				parents = append(parents, label)
+			}
+			labels[y*width+x] = label
+		}
+	}
+
+	// second pass, resolve to the final labels and gather the stats.
+	final := make([]int, len(parents))
+	result := &Labels{Width: width, Height: height, Labels: labels}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			idx := y*width + x
+			if labels[idx] == 0 {
+				continue
+			}
+
+			root := findRoot(parents, labels[idx])
+			if final[root] == 0 {
+				result.Stats = append(result.Stats, ComponentStats{Label: len(result.Stats) + 1, Bounds: image.Rect(x, y, x+1, y+1)})
+				final[root] = len(result.Stats)
+			}
+			labels[idx] = final[root]
+
+			stats := &result.Stats[final[root]-1]
+			stats.Area++
+			stats.Bounds = stats.Bounds.Union(image.Rect(x, y, x+1, y+1))
+			stats.CentroidX += float64(x)
+			stats.CentroidY += float64(y)
+		}
+	}
+
+	for i := range result.Stats {
+		result.Stats[i].CentroidX /= float64(result.Stats[i].Area)
+		result.Stats[i].CentroidY /= float64(result.Stats[i].Area)
+	}
+	return result
+}
+
+// findRoot returns the root of label, compressing the path as it goes.
+func findRoot(parents []int, label int) int {
+	for parents[label] != label {
+		parents[label] = parents[parents[label]]
+		label = parents[label]
+	}
+	return label
+}
+
+// union joins the sets containing a and b, returning the root (the lower label) of the combined set.
+func union(parents []int, a int, b int) int {
+	ra := findRoot(parents, a)
+	rb := findRoot(parents, b)
+	if ra > rb {
+		ra, rb = rb, ra
+	}
+	parents[rb] = ra
+	return ra
+}
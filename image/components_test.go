@@ -0,0 +1,90 @@
+package image
+
+import (
+	"image"
+	"slices"
+	"testing"
+
+	"github.com/kpfaulkner/borders/common"
+)
+
+// TestLabelComponents tests labelling and stats of connected components.
+func TestLabelComponents(t *testing.T) {
+	testCases := []struct {
+		name           string
+		rows           []string
+		padded         bool
+		connectivity   common.Connectivity
+		expectedLabels []int
+		expectedStats  []ComponentStats
+	}{
+		{
+			name:           "success 8 connected diagonal",
+			rows:           []string{"#..", ".#.", "..#"},
+			connectivity:   common.Connectivity8,
+			expectedLabels: []int{1, 0, 0, 0, 1, 0, 0, 0, 1},
+			expectedStats:  []ComponentStats{{Label: 1, Area: 3, Bounds: image.Rect(0, 0, 3, 3), CentroidX: 1, CentroidY: 1}},
+		},
+		{
+			name:           "success 4 connected diagonal",
+			rows:           []string{"#..", ".#.", "..#"},
+			connectivity:   common.Connectivity4,
+			expectedLabels: []int{1, 0, 0, 0, 2, 0, 0, 0, 3},
+			expectedStats: []ComponentStats{
+				{Label: 1, Area: 1, Bounds: image.Rect(0, 0, 1, 1), CentroidX: 0, CentroidY: 0},
+				{Label: 2, Area: 1, Bounds: image.Rect(1, 1, 2, 2), CentroidX: 1, CentroidY: 1},
+				{Label: 3, Area: 1, Bounds: image.Rect(2, 2, 3, 3), CentroidX: 2, CentroidY: 2},
+			},
+		},
+		{
+			name:           "success merged U shapes",
+			rows:           []string{"#.#.#", "#.#.#", "#####", "....."},
+			connectivity:   common.Connectivity4,
+			expectedLabels: []int{1, 0, 1, 0, 1, 1, 0, 1, 0, 1, 1, 1, 1, 1, 1, 0, 0, 0, 0, 0},
+			expectedStats:  []ComponentStats{{Label: 1, Area: 11, Bounds: image.Rect(0, 0, 5, 3), CentroidX: 2, CentroidY: 13.0 / 11.0}},
+		},
+		{
+			name:           "success padding removed",
+			rows:           []string{".....", ".#...", "...#.", "....."},
+			padded:         true,
+			connectivity:   common.Connectivity8,
+			expectedLabels: []int{1, 0, 0, 0, 0, 2},
+			expectedStats: []ComponentStats{
+				{Label: 1, Area: 1, Bounds: image.Rect(0, 0, 1, 1), CentroidX: 0, CentroidY: 0},
+				{Label: 2, Area: 1, Bounds: image.Rect(2, 1, 3, 2), CentroidX: 2, CentroidY: 1},
+			},
+		},
+		{
+			name:          "success empty",
+			rows:          []string{"...", "..."},
+			connectivity:  common.Connectivity8,
+			expectedStats: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// rows include the padding.
+			padding := 0
+			if tc.padded {
+				padding = 2
+			}
+			img := common.NewSuzukiImage(len(tc.rows[0])-padding, len(tc.rows)-padding, tc.padded)
+			for y, row := range tc.rows {
+				for x, ch := range row {
+					if ch == '#' {
+						img.SetXY(x, y, 1)
+					}
+				}
+			}
+
+			labels := LabelComponents(img, tc.connectivity)
+			if tc.expectedLabels != nil && !slices.Equal(labels.Labels, tc.expectedLabels) {
+				t.Errorf("expected labels %v, got %v", tc.expectedLabels, labels.Labels)
+			}
+			if !slices.Equal(labels.Stats, tc.expectedStats) {
+				t.Errorf("expected stats %+v, got %+v", tc.expectedStats, labels.Stats)
+			}
+		})
+	}
+}
@@ -8,4 +8,11 @@
 //   Dilate: This modifies the suzuki image, based on Morphological Dilation
 //   https://en.wikipedia.org/wiki/Dilation_(morphology)
 
+//   FillHoles: This fills areas of background not connected to the edge of the suzuki image.
+
+//   RemoveSmallObjects: This removes connected areas of foreground smaller than a given number of pixels.
+
+//   LabelComponents: This labels the connected components of the suzuki image, along with the area, bounds
+//   and centroid of each.
+
 package image
@@ -1,6 +1,8 @@
 package image
 
 import (
+	"context"
+
 	"github.com/kpfaulkner/borders/common"
 )
 
@@ -8,9 +10,19 @@ import (
 // https://en.wikipedia.org/wiki/Erosion_(morphology)
 // Although based on the above, we always need to make sure the border of the image is all 0.
 func Erode(img *common.SuzukiImage, radius int) (*common.SuzukiImage, error) {
+	return ErodeCtx(context.Background(), img, radius, nil)
+}
 
-	img2 := common.NewSuzukiImage(img.Width, img.Height, img.HasPadding())
+// ErodeCtx is Erode with cancellation and progress reporting. The context is checked at the start of each row
+// and progress (if not nil) is called after each row.
+func ErodeCtx(ctx context.Context, img *common.SuzukiImage, radius int, progress common.ProgressFunc) (*common.SuzukiImage, error) {
+
+	img2 := common.NewSuzukiImageWithStorage(img.Width, img.Height, img.HasPadding(), img.Storage())
 	for y := 0; y < img.Height; y++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		for x := 0; x < img.Width; x++ {
 
 			// if x == 0 or y == 0 or x == img.Width-1 or y == img.Height-1 then its an edge, and set it to 0.
@@ -31,6 +43,10 @@ func Erode(img *common.SuzukiImage, radius int) (*common.SuzukiImage, error) {
 				}
 			}
 		}
+
+		if progress != nil {
+			progress(common.Progress{RowsScanned: y + 1, TotalRows: img.Height})
+		}
 	}
 	return img2, nil
 }
@@ -52,9 +68,19 @@ func checkErodeRadius(img *common.SuzukiImage, x int, y int, width int, height i
 // Dilate the suzuki image, based on Morphological Dilation
 // https://en.wikipedia.org/wiki/Dilation_(morphology)
 func Dilate(img *common.SuzukiImage, radius int) (*common.SuzukiImage, error) {
-	img2 := common.NewSuzukiImage(img.Width, img.Height, img.HasPadding())
+	return DilateCtx(context.Background(), img, radius, nil)
+}
+
+// DilateCtx is Dilate with cancellation and progress reporting. The context is checked at the start of each row
+// and progress (if not nil) is called after each row.
+func DilateCtx(ctx context.Context, img *common.SuzukiImage, radius int, progress common.ProgressFunc) (*common.SuzukiImage, error) {
+	img2 := common.NewSuzukiImageWithStorage(img.Width, img.Height, img.HasPadding(), img.Storage())
 
 	for y := 0; y < img.Height; y++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		for x := 0; x < img.Width; x++ {
 
 			// for each pixel, check if any pixels within radius are 1
@@ -63,6 +89,10 @@ func Dilate(img *common.SuzukiImage, radius int) (*common.SuzukiImage, error) {
 				dilateRadiusAroundPoint(img2, x, y, img.Width, img.Height, radius)
 			}
 		}
+
+		if progress != nil {
+			progress(common.Progress{RowsScanned: y + 1, TotalRows: img.Height})
+		}
 	}
 	return img2, nil
 }
@@ -1,6 +1,8 @@
 package image
 
 import (
+	"context"
+	"errors"
 	"image"
 	"testing"
 
@@ -150,3 +152,48 @@ func createFullSuzukiImage(width int, height int) *common.SuzukiImage {
 	}
 	return si
 }
+
+// TestMorphologyCtx tests cancellation and progress reporting of ErodeCtx and DilateCtx.
+func TestMorphologyCtx(t *testing.T) {
+	ops := map[string]func(ctx context.Context, img *common.SuzukiImage, radius int, progress common.ProgressFunc) (*common.SuzukiImage, error){
+		"erode":  ErodeCtx,
+		"dilate": DilateCtx,
+	}
+
+	for name, op := range ops {
+		t.Run(name+" success", func(t *testing.T) {
+			img := createSuzukiImage(10, 10, nil)
+			rows := 0
+			_, err := op(context.Background(), img, 1, func(p common.Progress) {
+				rows = p.RowsScanned
+				if p.TotalRows != 10 {
+					t.Errorf("expected total rows 10, got %d", p.TotalRows)
+				}
+			})
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if rows != 10 {
+				t.Errorf("expected 10 rows scanned, got %d", rows)
+			}
+		})
+
+		t.Run(name+" error when cancelled", func(t *testing.T) {
+			img := createSuzukiImage(10, 10, nil)
+			ctx, cancel := context.WithCancel(context.Background())
+			rows := 0
+			_, err := op(ctx, img, 1, func(p common.Progress) {
+				rows = p.RowsScanned
+				if rows == 3 {
+					cancel()
+				}
+			})
+			if !errors.Is(err, context.Canceled) {
+				t.Fatalf("expected context.Canceled, got %v", err)
+			}
+			if rows != 3 {
+				t.Errorf("expected 3 rows scanned, got %d", rows)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+# Generates results/opencv/moments.json, the OpenCV reference values used by TestMomentsOpenCVReference
+# in border/moments_test.go. Run from this directory: python3 moments.py
+#
+# With cv2 installed the contours and values come straight from cv2.findContours, cv2.moments and
+# cv2.HuMoments. Without it, the contours are read from the recorded OpenCV output of test.png
+# (results/opencv/test1-results.log) and the values are calculated by a pure python transcription of
+# OpenCV's contourMoments, completeMomentState and HuMoments (modules/imgproc/src/moments.cpp), keeping
+# OpenCV's order of operations. The "generator" field of each entry records which was used.
+import json
+import math
+import sys
+
+try:
+    import cv2
+    import numpy as np
+except ImportError:
+    cv2 = None
+
+# non-convex contour, matching TestMomentsIrregular.
+IRREGULAR = [[0, 0], [5, 1], [7, 6], [3, 4], [1, 7], [-2, 3]]
+
+FLT_EPSILON = 1.1920928955078125e-07
+DBL_EPSILON = 2.220446049250313e-16
+
+
+def contour_moments(points):
+    """Transcription of cv::contourMoments for integer points."""
+    m = dict.fromkeys(["m00", "m10", "m01", "m20", "m11", "m02", "m30", "m21", "m12", "m03"], 0.0)
+    lpt = len(points)
+    if lpt == 0:
+        return complete_moment_state(m)
+
+    a00 = a10 = a01 = a20 = a11 = a02 = a30 = a21 = a12 = a03 = 0.0
+    xi_1 = float(points[-1][0])
+    yi_1 = float(points[-1][1])
+    xi_12 = xi_1 * xi_1
+    yi_12 = yi_1 * yi_1
+    for x, y in points:
+        xi = float(x)
+        yi = float(y)
+        xi2 = xi * xi
+        yi2 = yi * yi
+        dxy = xi_1 * yi - xi * yi_1
+        xii_1 = xi_1 + xi
+        yii_1 = yi_1 + yi
+
+        a00 += dxy
+        a10 += dxy * xii_1
+        a01 += dxy * yii_1
+        a20 += dxy * (xi_1 * xii_1 + xi2)
+        a11 += dxy * (xi_1 * (yii_1 + yi_1) + xi * (yii_1 + yi))
+        a02 += dxy * (yi_1 * yii_1 + yi2)
+        a30 += dxy * xii_1 * (xi_12 + xi2)
+        a03 += dxy * yii_1 * (yi_12 + yi2)
+        a21 += dxy * (xi_12 * (3 * yi_1 + yi) + 2 * xi * xi_1 * yii_1 + xi2 * (yi_1 + 3 * yi))
+        a12 += dxy * (yi_12 * (3 * xi_1 + xi) + 2 * yi * yi_1 * xii_1 + yi2 * (xi_1 + 3 * xi))
+        xi_1 = xi
+        yi_1 = yi
+        xi_12 = xi2
+        yi_12 = yi2
+
+    if abs(a00) > FLT_EPSILON:
+        sign = 1.0 if a00 > 0 else -1.0
+        db1_2 = sign * 0.5
+        db1_6 = sign * 0.16666666666666666666666666666667
+        db1_12 = sign * 0.083333333333333333333333333333333
+        db1_24 = sign * 0.041666666666666666666666666666667
+        db1_20 = sign * 0.05
+        db1_60 = sign * 0.016666666666666666666666666666667
+
+        m["m00"] = a00 * db1_2
+        m["m10"] = a10 * db1_6
+        m["m01"] = a01 * db1_6
+        m["m20"] = a20 * db1_12
+        m["m11"] = a11 * db1_24
+        m["m02"] = a02 * db1_12
+        m["m30"] = a30 * db1_20
+        m["m21"] = a21 * db1_60
+        m["m12"] = a12 * db1_60
+        m["m03"] = a03 * db1_20
+    return complete_moment_state(m)
+
+
+def complete_moment_state(m):
+    """Transcription of cv::Moments::completeMomentState (as used by the cv::Moments constructor)."""
+    cx = cy = inv_m00 = 0.0
+    if abs(m["m00"]) > DBL_EPSILON:
+        inv_m00 = 1.0 / m["m00"]
+        cx = m["m10"] * inv_m00
+        cy = m["m01"] * inv_m00
+
+    mu20 = m["m20"] - m["m10"] * cx
+    mu11 = m["m11"] - m["m10"] * cy
+    mu02 = m["m02"] - m["m01"] * cy
+    m["mu20"] = mu20
+    m["mu11"] = mu11
+    m["mu02"] = mu02
+    m["mu30"] = m["m30"] - cx * (3 * mu20 + cx * m["m10"])
+    mu11 += mu11
+    m["mu21"] = m["m21"] - cx * (mu11 + cx * m["m01"]) - cy * mu20
+    m["mu12"] = m["m12"] - cy * (mu11 + cy * m["m10"]) - cx * mu02
+    m["mu03"] = m["m03"] - cy * (3 * mu02 + cy * m["m01"])
+
+    inv_sqrt_m00 = math.sqrt(abs(inv_m00))
+    s2 = inv_m00 * inv_m00
+    s3 = s2 * inv_sqrt_m00
+    for k in ["20", "11", "02"]:
+        m["nu" + k] = m["mu" + k] * s2
+    for k in ["30", "21", "12", "03"]:
+        m["nu" + k] = m["mu" + k] * s3
+    return m
+
+
+def hu_moments(m):
+    """Transcription of cv::HuMoments."""
+    t0 = m["nu30"] + m["nu12"]
+    t1 = m["nu21"] + m["nu03"]
+    q0 = t0 * t0
+    q1 = t1 * t1
+    n4 = 4 * m["nu11"]
+    s = m["nu20"] + m["nu02"]
+    d = m["nu20"] - m["nu02"]
+
+    hu = [0.0] * 7
+    hu[0] = s
+    hu[1] = d * d + n4 * m["nu11"]
+    hu[3] = q0 + q1
+    hu[5] = d * (q0 - q1) + n4 * t0 * t1
+
+    t0 *= q0 - 3 * q1
+    t1 *= 3 * q0 - q1
+
+    q0 = m["nu30"] - 3 * m["nu12"]
+    q1 = 3 * m["nu21"] - m["nu03"]
+
+    hu[2] = q0 * q0 + q1 * q1
+    hu[4] = q0 * t0 + q1 * t1
+    hu[6] = q1 * t0 - q0 * t1
+    return hu
+
+
+def recorded_contours(filename):
+    """Reads the contours printed one point per line by OpenCV, splitting where consecutive points aren't neighbours."""
+    contours = []
+    prev = None
+    with open(filename) as f:
+        for line in f:
+            line = line.strip()
+            if not line.startswith("("):
+                continue
+            p = [int(v) for v in line[1:-1].split(",")]
+            if prev is None or max(abs(p[0] - prev[0]), abs(p[1] - prev[1])) > 1:
+                contours.append([])
+            contours[-1].append(p)
+            prev = p
+    return contours
+
+
+def main():
+    if cv2 is not None:
+        generator = "cv2 " + cv2.__version__
+        img = cv2.imread("test.png", cv2.IMREAD_GRAYSCALE)
+        _, binary = cv2.threshold(img, 0, 1, cv2.THRESH_BINARY)
+        found, _ = cv2.findContours(binary, cv2.RETR_LIST, cv2.CHAIN_APPROX_NONE)
+        contours = [c.reshape(-1, 2).tolist() for c in found]
+    else:
+        generator = "transcription of OpenCV moments.cpp"
+        print("cv2 not available, using " + generator, file=sys.stderr)
+        contours = recorded_contours("results/opencv/test1-results.log")
+
+    entries = [("irregular", IRREGULAR)] + [("test.png contour %d" % i, c) for i, c in enumerate(contours)]
+
+    references = []
+    for name, points in entries:
+        if cv2 is not None:
+            moments = cv2.moments(np.array(points, dtype=np.int32).reshape(-1, 1, 2))
+            hu = cv2.HuMoments(moments).flatten().tolist()
+        else:
+            moments = contour_moments(points)
+            hu = hu_moments(moments)
+        references.append({
+            "name": name,
+            "generator": generator,
+            "points": points,
+            "moments": moments,
+            "hu": hu,
+        })
+
+    with open("results/opencv/moments.json", "w") as f:
+        json.dump(references, f, indent=1)
+
+
+if __name__ == "__main__":
+    main()